@@ -0,0 +1,169 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package crud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+// Context returns the context the resource's API calls run under.
+func (s *BaseCrud) Context() context.Context {
+	if s.Client == nil {
+		return context.Background()
+	}
+	return s.Client.Context()
+}
+
+// SetContext makes the resource's API calls run under ctx.
+func (s *BaseCrud) SetContext(ctx context.Context) {
+	if s.Client != nil {
+		s.Client = s.Client.WithContext(ctx)
+	}
+}
+
+func resourceContext(sync interface{}) context.Context {
+	if cr, ok := sync.(ContextualResource); ok {
+		return cr.Context()
+	}
+	return context.Background()
+}
+
+// withTimeout bounds the API calls that sync makes with timeout, until
+// restore is called.
+func withTimeout(sync interface{}, timeout time.Duration) (timeoutCtx context.Context, restore func()) {
+	cr, ok := sync.(ContextualResource)
+	if !ok || timeout <= 0 {
+		return resourceContext(sync), func() {}
+	}
+
+	parent := cr.Context()
+	timeoutCtx, cancel := context.WithTimeout(parent, timeout)
+	cr.SetContext(timeoutCtx)
+	return timeoutCtx, func() {
+		cancel()
+		cr.SetContext(parent)
+	}
+}
+
+// refreshGracePeriod bounds how long an interrupted wait lets a refresh that
+// is in flight finish. Its API calls share the cancelled context, so it
+// normally fails straight away.
+const refreshGracePeriod = 5 * time.Second
+
+// waitForState is conf.WaitForState(), but returns as soon as ctx is done
+// rather than after the next poll.
+//
+// The polling goroutine can outlive the wait, so once ctx is done its
+// refreshes return without calling conf.Refresh, and the wait only returns
+// once no refresh is in flight. The goroutine then stops at its next poll
+// without touching the resource.
+func waitForState(ctx context.Context, conf *resource.StateChangeConf) (interface{}, error) {
+	refreshing := make(chan struct{}, 1)
+	refresh := conf.Refresh
+	guarded := *conf
+	guarded.Refresh = func() (interface{}, string, error) {
+		refreshing <- struct{}{}
+		defer func() { <-refreshing }()
+		if err := ctx.Err(); err != nil {
+			return nil, "", err
+		}
+		return refresh()
+	}
+
+	type result struct {
+		res interface{}
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		res, err := guarded.WaitForState()
+		done <- result{res, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.res, r.err
+	case <-ctx.Done():
+		select {
+		case <-done:
+		case refreshing <- struct{}{}:
+			// No refresh is in flight, and none will call conf.Refresh again.
+			<-refreshing
+		case <-time.After(refreshGracePeriod):
+			log.Printf("[WARN] crud.waitForState: a refresh was still in flight %s after the wait was interrupted", refreshGracePeriod)
+		}
+		return nil, interruptedError(ctx, conf.Target)
+	}
+}
+
+// Retry is resource.Retry, but stops as soon as the resource's context is
+// done, and bounds the API calls that f makes with timeout.
+func Retry(r interface{}, timeout time.Duration, f resource.RetryFunc) error {
+	ctx := resourceContext(r)
+	_, restore := withTimeout(r, timeout)
+	defer restore()
+
+	var lastErr error
+	var mutex sync.Mutex
+	conf := &resource.StateChangeConf{
+		Pending:    []string{"retryableerror"},
+		Target:     []string{"success"},
+		Timeout:    timeout,
+		MinTimeout: 500 * time.Millisecond,
+		Refresh: func() (interface{}, string, error) {
+			rerr := f()
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if rerr == nil {
+				lastErr = nil
+				return 42, "success", nil
+			}
+			lastErr = rerr.Err
+			if rerr.Retryable {
+				return 42, "retryableerror", nil
+			}
+			return nil, "quit", rerr.Err
+		},
+	}
+
+	_, waitErr := waitForState(ctx, conf)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if ctx.Err() != nil {
+		return interruptedError(ctx, nil)
+	}
+	// As with resource.Retry, the last error f returned says more than the
+	// timeout does.
+	if lastErr == nil {
+		return waitErr
+	}
+	return lastErr
+}
+
+// sleep waits for d, or until the resource's context is done.
+func sleep(sync interface{}, d time.Duration) error {
+	ctx := resourceContext(sync)
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return interruptedError(ctx, nil)
+	}
+}
+
+func interruptedError(ctx context.Context, target []string) error {
+	if len(target) == 0 {
+		return fmt.Errorf("Interrupted: %s", ctx.Err())
+	}
+	return fmt.Errorf("Interrupted while waiting for state to become %v: %s", target, ctx.Err())
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package crud
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/oracle/bmcs-go-sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+// instanceCrud polls an instance until it is RUNNING.
+type instanceCrud struct {
+	BaseCrud
+	Resource *baremetal.Instance
+}
+
+func (s *instanceCrud) Get() (e error) {
+	s.Resource, e = s.Client.GetInstance(s.D.Id())
+	return
+}
+
+func (s *instanceCrud) SetData() {}
+
+// newHangingInstanceCrud returns an instance whose first poll reports it
+// PROVISIONING, and whose later polls hang until they are cancelled.
+func newHangingInstanceCrud(t *testing.T, ctx context.Context) (s *instanceCrud, hanging, cancelled chan struct{}, closeServer func()) {
+	hanging = make(chan struct{}, 1)
+	cancelled = make(chan struct{}, 1)
+	polls := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&polls, 1) > 1 {
+			hanging <- struct{}{}
+			<-r.Context().Done()
+			cancelled <- struct{}{}
+			return
+		}
+		w.Header().Set("content-type", "application/json")
+		w.Write([]byte(`{"id":"ocid1.instance.oc1..standin","lifecycleState":"PROVISIONING"}`))
+	}))
+
+	client := newStandInClient(t, server)
+
+	d := (&schema.Resource{Schema: map[string]*schema.Schema{
+		"state": {Type: schema.TypeString, Computed: true},
+	}}).Data(nil)
+	d.SetId("ocid1.instance.oc1..standin")
+
+	s = &instanceCrud{BaseCrud: BaseCrud{D: d, Client: client.WithContext(ctx)}}
+	return s, hanging, cancelled, server.Close
+}
+
+// newStandInClient returns a client that sends its requests to server.
+func newStandInClient(t *testing.T, server *httptest.Server) *baremetal.Client {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	client, err := baremetal.NewClient("ocid1.user.oc1..standin", "ocid1.tenancy.oc1..standin", "00:00",
+		baremetal.PrivateKeyBytes(keyPEM), baremetal.UrlTemplate(server.URL+"/%s/%s"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func assertCancelled(t *testing.T, cancelled chan struct{}) {
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Error("The hung poll was not cancelled")
+	}
+}
+
+func TestWaitForStateRefresh_interrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s, hanging, cancelled, closeServer := newHangingInstanceCrud(t, ctx)
+	defer closeServer()
+
+	go func() {
+		<-hanging
+		cancel()
+	}()
+	start := time.Now()
+	err := waitForStateRefresh(s, time.Hour, []string{baremetal.ResourceProvisioning}, []string{baremetal.ResourceRunning})
+
+	assert.True(t, time.Since(start) < 5*time.Second)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Interrupted while waiting for state to become [RUNNING]")
+	}
+	// The hung poll is cancelled too, not just abandoned.
+	assertCancelled(t, cancelled)
+}
+
+func TestWaitForStateRefresh_timeoutCancelsPoll(t *testing.T) {
+	s, _, cancelled, closeServer := newHangingInstanceCrud(t, context.Background())
+	defer closeServer()
+
+	start := time.Now()
+	err := waitForStateRefresh(s, 500*time.Millisecond, []string{baremetal.ResourceProvisioning}, []string{baremetal.ResourceRunning})
+
+	assert.True(t, time.Since(start) < 5*time.Second)
+	if assert.NotNil(t, err) {
+		_, isTimeout := err.(*resource.TimeoutError)
+		assert.True(t, isTimeout, err.Error())
+	}
+	assertCancelled(t, cancelled)
+	// The resource's context is restored after the wait.
+	assert.Nil(t, s.Context().Err())
+}
+
+func TestWaitForState_interruptedWaitsForRefreshInFlight(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	inFlight := make(chan struct{})
+	finish := make(chan struct{})
+	var refreshes, finished int32
+	conf := &resource.StateChangeConf{
+		Pending: []string{baremetal.ResourceProvisioning},
+		Target:  []string{baremetal.ResourceRunning},
+		Timeout: time.Hour,
+		Refresh: func() (interface{}, string, error) {
+			if atomic.AddInt32(&refreshes, 1) == 2 {
+				close(inFlight)
+				<-finish
+				atomic.StoreInt32(&finished, 1)
+			}
+			return "instance", baremetal.ResourceProvisioning, nil
+		},
+	}
+
+	go func() {
+		<-inFlight
+		cancel()
+		time.Sleep(200 * time.Millisecond)
+		close(finish)
+	}()
+	_, err := waitForState(ctx, conf)
+
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Interrupted while waiting for state to become [RUNNING]")
+	}
+	// The refresh in flight finished before the wait returned...
+	assert.Equal(t, int32(1), atomic.LoadInt32(&finished))
+	// ...and no other refresh started afterwards.
+	time.Sleep(500 * time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&refreshes))
+}
+
+func TestRetry(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	client := newStandInClient(t, server)
+
+	// Once it times out, the last error is returned.
+	var attempts int32
+	err := Retry(&instanceCrud{BaseCrud: BaseCrud{Client: client}}, time.Second, func() *resource.RetryError {
+		return resource.RetryableError(fmt.Errorf("attempt %d", atomic.AddInt32(&attempts, 1)))
+	})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, fmt.Sprintf("attempt %d", atomic.LoadInt32(&attempts)), err.Error())
+	}
+
+	// Interrupted, it returns straight away, and stops retrying.
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts = 0
+	start := time.Now()
+	err = Retry(&instanceCrud{BaseCrud: BaseCrud{Client: client.WithContext(ctx)}}, time.Hour, func() *resource.RetryError {
+		if atomic.AddInt32(&attempts, 1) == 2 {
+			cancel()
+		}
+		return resource.RetryableError(errors.New("not yet"))
+	})
+	assert.True(t, time.Since(start) < 5*time.Second)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Interrupted: context canceled", err.Error())
+	}
+	time.Sleep(time.Second)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package crud
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/oracle/bmcs-go-sdk"
+)
+
+// ErrorClass is a coarse classification of a failed request. crud uses it
+// to decide whether a resource is gone, busy, or whether the request is
+// worth polling again.
+type ErrorClass int
+
+const (
+	ErrorClassUnknown ErrorClass = iota
+	// The resource does not exist, or the caller is not allowed to see it.
+	ErrorClassNotFound
+	// The resource is in a state that conflicts with the request.
+	ErrorClassConflict
+	// The service is throttling the caller.
+	ErrorClassThrottled
+	// The service failed to handle the request (5xx).
+	ErrorClassServiceError
+	// An If-Match condition did not hold: the resource has changed.
+	ErrorClassPreconditionFailed
+	// The resource being created already exists, or the caller is not
+	// allowed to create it. Polling again will not help.
+	ErrorClassAlreadyExists
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassNotFound:
+		return "NotFound"
+	case ErrorClassConflict:
+		return "Conflict"
+	case ErrorClassThrottled:
+		return "Throttled"
+	case ErrorClassServiceError:
+		return "ServiceError"
+	case ErrorClassPreconditionFailed:
+		return "PreconditionFailed"
+	case ErrorClassAlreadyExists:
+		return "AlreadyExists"
+	}
+	return "Unknown"
+}
+
+// MissingResourceError reports a resource that could not be found by a
+// lookup that does not map to a single API call, e.g. scanning a list or a
+// map nested in a parent resource.
+type MissingResourceError struct {
+	Message string
+}
+
+func (e *MissingResourceError) Error() string {
+	return e.Message
+}
+
+// NewMissingResourceError formats a MissingResourceError.
+func NewMissingResourceError(format string, a ...interface{}) error {
+	return &MissingResourceError{Message: fmt.Sprintf(format, a...)}
+}
+
+// ClassifyError maps an error returned by the SDK, or by a ResourceFetcher,
+// onto an ErrorClass using the HTTP status and service error code rather
+// than the error text.
+func ClassifyError(err error) ErrorClass {
+	switch e := err.(type) {
+	case *baremetal.Error:
+		return classifyAPIError(e)
+	case *MissingResourceError:
+		return ErrorClassNotFound
+	case *resource.NotFoundError:
+		return ErrorClassNotFound
+	}
+	return ErrorClassUnknown
+}
+
+func classifyAPIError(e *baremetal.Error) ErrorClass {
+	status, err := strconv.Atoi(e.Status)
+	if err != nil {
+		return ErrorClassUnknown
+	}
+
+	switch {
+	case status == http.StatusNotFound:
+		// NotAuthorizedOrNotFound is deliberately ambiguous; either way the
+		// resource is not there for this caller.
+		return ErrorClassNotFound
+	case status == http.StatusConflict:
+		return classifyConflict(e.Code)
+	case status == http.StatusTooManyRequests:
+		return ErrorClassThrottled
+	case status == http.StatusPreconditionFailed:
+		return ErrorClassPreconditionFailed
+	case status >= http.StatusInternalServerError:
+		return ErrorClassServiceError
+	}
+	return ErrorClassUnknown
+}
+
+// classifyConflict splits 409s by service error code: only some of them
+// mean the resource is in a state that conflicts with the request.
+func classifyConflict(code string) ErrorClass {
+	switch {
+	case code == "InvalidatedRetryToken":
+		// The retry token was already used for a different request; the
+		// request itself is at fault, not the resource.
+		return ErrorClassUnknown
+	case strings.HasSuffix(code, "AlreadyExists"):
+		// NotAuthorizedOrResourceAlreadyExists, UserAlreadyExists,
+		// CompartmentAlreadyExists, ...
+		return ErrorClassAlreadyExists
+	}
+	return ErrorClassConflict
+}
+
+// IsNotFound is true when err means the resource is gone.
+func IsNotFound(err error) bool {
+	return ClassifyError(err) == ErrorClassNotFound
+}
+
+// IsConflict is true when err is a 409 from the service reporting that the
+// resource is in a state that conflicts with the request.
+func IsConflict(err error) bool {
+	return ClassifyError(err) == ErrorClassConflict
+}
+
+// IsAlreadyExists is true when err is a 409 from the service reporting that
+// the resource already exists or may not be created by the caller.
+func IsAlreadyExists(err error) bool {
+	return ClassifyError(err) == ErrorClassAlreadyExists
+}
+
+// IsPreconditionFailed is true when err is a 412 from the service, i.e. an
+// If-Match that did not match the resource's current ETag.
+func IsPreconditionFailed(err error) bool {
+	return ClassifyError(err) == ErrorClassPreconditionFailed
+}
+
+// IsRetryable is true when err is transient (throttling or a service
+// failure) and the same request may succeed if polled again.
+func IsRetryable(err error) bool {
+	switch ClassifyError(err) {
+	case ErrorClassThrottled, ErrorClassServiceError:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package crud
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/oracle/bmcs-go-sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected ErrorClass
+	}{
+		{&baremetal.Error{Status: "404", Code: baremetal.NotAuthorizedOrNotFound}, ErrorClassNotFound},
+		// The load balancer service reports missing children with a 404 and free text.
+		{&baremetal.Error{Status: "404", Code: "NotFound", Message: "Load balancer ocid1.loadbalancer.x has no backend set named bs"}, ErrorClassNotFound},
+		{&baremetal.Error{Status: "409", Code: "IncorrectState"}, ErrorClassConflict},
+		{&baremetal.Error{Status: "409", Code: "Conflict"}, ErrorClassConflict},
+		// Not every 409 is about the resource's state.
+		{&baremetal.Error{Status: "409", Code: "NotAuthorizedOrResourceAlreadyExists"}, ErrorClassAlreadyExists},
+		{&baremetal.Error{Status: "409", Code: baremetal.UserAlreadyExists}, ErrorClassAlreadyExists},
+		{&baremetal.Error{Status: "409", Code: "InvalidatedRetryToken"}, ErrorClassUnknown},
+		{&baremetal.Error{Status: "429", Code: "TooManyRequests"}, ErrorClassThrottled},
+		{&baremetal.Error{Status: "412", Code: "NoEtagMatch"}, ErrorClassPreconditionFailed},
+		{&baremetal.Error{Status: "500", Code: "InternalServerError"}, ErrorClassServiceError},
+		{&baremetal.Error{Status: "503", Code: "ServiceUnavailable"}, ErrorClassServiceError},
+		// Messages alone must not be enough to void a resource.
+		{&baremetal.Error{Status: "400", Code: baremetal.InvalidParameter, Message: "subnet does not exist"}, ErrorClassUnknown},
+		// A missing related resource is a bad request, not a missing resource.
+		{&baremetal.Error{Status: "400", Code: "RelatedResourceNotAuthorizedOrNotFound"}, ErrorClassUnknown},
+		{errors.New("vnic not found"), ErrorClassUnknown},
+		{NewMissingResourceError("Listener %s does not exist", "l"), ErrorClassNotFound},
+		{&resource.NotFoundError{Retries: 20}, ErrorClassNotFound},
+		{nil, ErrorClassUnknown},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, ClassifyError(c.err), "%v", c.err)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, IsRetryable(&baremetal.Error{Status: "429"}))
+	assert.True(t, IsRetryable(&baremetal.Error{Status: "502"}))
+	assert.False(t, IsRetryable(&baremetal.Error{Status: "404"}))
+	assert.False(t, IsRetryable(&baremetal.Error{Status: "409"}))
+	assert.False(t, IsRetryable(&baremetal.Error{Status: "409", Code: "NotAuthorizedOrResourceAlreadyExists"}))
+}
+
+func TestIsConflict(t *testing.T) {
+	assert.True(t, IsConflict(&baremetal.Error{Status: "409", Code: "IncorrectState"}))
+	assert.False(t, IsConflict(&baremetal.Error{Status: "409", Code: "NotAuthorizedOrResourceAlreadyExists"}))
+	assert.True(t, IsAlreadyExists(&baremetal.Error{Status: "409", Code: "NotAuthorizedOrResourceAlreadyExists"}))
+	assert.False(t, IsAlreadyExists(&baremetal.Error{Status: "409", Code: "IncorrectState"}))
+}
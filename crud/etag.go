@@ -0,0 +1,87 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package crud
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/oracle/bmcs-go-sdk"
+)
+
+// ETagSchema is the "etag" attribute of resources whose updates and deletes
+// are conditional on the resource being unchanged since it was last read.
+func ETagSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+}
+
+// IfMatch returns the ETag recorded at the last create, read or update, to
+// send as If-Match. It is empty, and so not sent, for resources without an
+// "etag" attribute.
+func (s *BaseCrud) IfMatch() string {
+	etag, _ := s.D.Get("etag").(string)
+	return etag
+}
+
+// IfMatchOptions returns IfMatch() as options for the SDK's delete calls.
+func (s *BaseCrud) IfMatchOptions() *baremetal.IfMatchOptions {
+	return &baremetal.IfMatchOptions{IfMatch: s.IfMatch()}
+}
+
+// recordETag stores the ETag of sync.Res or sync.Resource, if it has one,
+// in sync.D's "etag" attribute.
+func recordETag(sync interface{}) {
+	v := reflect.ValueOf(sync)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	dValue := v.Elem().FieldByName("D")
+	if !dValue.IsValid() {
+		return
+	}
+	d, ok := dValue.Interface().(*schema.ResourceData)
+	if !ok || d == nil {
+		return
+	}
+	for _, key := range []string{"Res", "Resource"} {
+		resourceReferenceValue := v.Elem().FieldByName(key)
+		if !resourceReferenceValue.IsValid() || resourceReferenceValue.Kind() != reflect.Ptr || resourceReferenceValue.IsNil() {
+			continue
+		}
+		resourceValue := resourceReferenceValue.Elem()
+		if resourceValue.Kind() != reflect.Struct {
+			continue
+		}
+		if etagValue := resourceValue.FieldByName("ETag"); etagValue.IsValid() && etagValue.String() != "" {
+			// Resources without an etag attribute don't make conditional
+			// requests, so there is nothing to record for them.
+			if err := d.Set("etag", etagValue.String()); err == nil {
+				log.Printf("[DEBUG] crud.recordETag: etag: %s", etagValue.String())
+			}
+			return
+		}
+	}
+}
+
+// PreconditionFailedError reports a conditional update or delete that was
+// rejected because the resource changed after Terraform last read it.
+type PreconditionFailedError struct {
+	ID  string
+	Err error
+}
+
+func (e *PreconditionFailedError) Error() string {
+	return fmt.Sprintf("Resource %s was changed outside of Terraform since it was last read (%s). "+
+		"Run terraform refresh, then plan and apply again.", e.ID, e.Err)
+}
+
+func handlePreconditionFailedError(d *schema.ResourceData, err *error) {
+	if *err != nil && IsPreconditionFailed(*err) {
+		*err = &PreconditionFailedError{ID: d.Id(), Err: *err}
+	}
+}
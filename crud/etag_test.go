@@ -0,0 +1,118 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package crud
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/oracle/bmcs-go-sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+// drgCrud updates and deletes a DRG conditionally on its ETag.
+type drgCrud struct {
+	BaseCrud
+	Res *baremetal.Drg
+}
+
+func (s *drgCrud) ID() string {
+	return s.Res.ID
+}
+
+func (s *drgCrud) Get() (e error) {
+	s.Res, e = s.Client.GetDrg(s.D.Id())
+	return
+}
+
+func (s *drgCrud) Update() (e error) {
+	opts := &baremetal.IfMatchDisplayNameOptions{}
+	opts.IfMatch = s.IfMatch()
+	opts.DisplayName = s.D.Get("display_name").(string)
+	s.Res, e = s.Client.UpdateDrg(s.D.Id(), opts)
+	return
+}
+
+func (s *drgCrud) Delete() (e error) {
+	return s.Client.DeleteDrg(s.D.Id(), s.IfMatchOptions())
+}
+
+func (s *drgCrud) SetData() {
+	s.D.Set("display_name", s.Res.DisplayName)
+}
+
+// newDrgStandIn serves a DRG whose ETag is currentETag, and which rejects
+// updates and deletes that don't match it. ifMatch records the If-Match
+// header of the last update or delete.
+func newDrgStandIn(t *testing.T, currentETag *string, ifMatch *string) (s *drgCrud, closeServer func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.Method != http.MethodGet {
+			*ifMatch = r.Header.Get("If-Match")
+			if *ifMatch != "" && *ifMatch != *currentETag {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				w.Write([]byte(`{"code":"NoEtagMatch","message":"The resource's etag does not match"}`))
+				return
+			}
+		}
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if r.Method == http.MethodPut {
+			*currentETag = "etag-updated"
+		}
+		w.Header().Set("ETag", *currentETag)
+		w.Write([]byte(`{"id":"ocid1.drg.oc1..standin","displayName":"drg","lifecycleState":"AVAILABLE"}`))
+	}))
+
+	d := (&schema.Resource{Schema: map[string]*schema.Schema{
+		"display_name": {Type: schema.TypeString, Optional: true},
+		"etag":         ETagSchema(),
+		"state":        {Type: schema.TypeString, Computed: true},
+	}}).Data(nil)
+	d.SetId("ocid1.drg.oc1..standin")
+
+	s = &drgCrud{BaseCrud: BaseCrud{D: d, Client: newStandInClient(t, server)}}
+	return s, server.Close
+}
+
+func TestETag_sentAsIfMatch(t *testing.T) {
+	currentETag, ifMatch := "etag-read", ""
+	s, closeServer := newDrgStandIn(t, &currentETag, &ifMatch)
+	defer closeServer()
+
+	assert.Nil(t, ReadResource(s))
+	assert.Equal(t, "etag-read", s.D.Get("etag"))
+
+	assert.Nil(t, UpdateResource(s.D, s))
+	assert.Equal(t, "etag-read", ifMatch)
+	// The update's response carries the new ETag, which the delete sends.
+	assert.Equal(t, "etag-updated", s.D.Get("etag"))
+
+	assert.Nil(t, DeleteResource(s.D, s))
+	assert.Equal(t, "etag-updated", ifMatch)
+	assert.Equal(t, "", s.D.Id())
+}
+
+func TestETag_changedOutsideTerraform(t *testing.T) {
+	currentETag, ifMatch := "etag-read", ""
+	s, closeServer := newDrgStandIn(t, &currentETag, &ifMatch)
+	defer closeServer()
+
+	assert.Nil(t, ReadResource(s))
+	currentETag = "etag-changed-elsewhere"
+
+	for _, err := range []error{UpdateResource(s.D, s), DeleteResource(s.D, s)} {
+		if assert.NotNil(t, err) {
+			_, isPreconditionFailed := err.(*PreconditionFailedError)
+			assert.True(t, isPreconditionFailed, err.Error())
+			assert.Contains(t, err.Error(), "ocid1.drg.oc1..standin was changed outside of Terraform")
+			assert.Contains(t, err.Error(), "Run terraform refresh")
+		}
+	}
+	// A failed delete leaves the resource in the state.
+	assert.Equal(t, "ocid1.drg.oc1..standin", s.D.Id())
+}
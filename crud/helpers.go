@@ -3,7 +3,9 @@
 package crud
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log"
 	"reflect"
 	"strconv"
@@ -27,6 +29,28 @@ var (
 	}
 )
 
+// WorkRequestRetryPolicy bounds how CreateResource resubmits a create whose
+// asynchronous work request ended up FAILED.
+type WorkRequestRetryPolicy struct {
+	// MaxRetries is the number of resubmissions after the first attempt.
+	MaxRetries int
+	// Backoff is the wait before the first resubmission. It doubles for
+	// each subsequent one, up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+func (p *WorkRequestRetryPolicy) backoff(retryNum int) time.Duration {
+	wait := p.Backoff
+	for i := 1; i < retryNum; i++ {
+		wait *= 2
+		if p.MaxBackoff > 0 && wait >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return wait
+}
+
 type BaseCrud struct {
 	D      *schema.ResourceData
 	Client *baremetal.Client
@@ -68,17 +92,10 @@ func (s *BaseCrud) State() string {
 }
 
 func handleMissingResourceError(sync ResourceVoider, err *error) {
-
-	if err != nil {
-		if strings.Contains((*err).Error(), "does not exist") ||
-			strings.Contains((*err).Error(), " not present in ") ||
-			strings.Contains((*err).Error(), "not found") ||
-			(strings.Contains((*err).Error(), "Load balancer") && strings.Contains((*err).Error(), " has no ")) {
-
-			log.Println("[DEBUG] Object does not exist, voiding resource and nullifying error")
-			sync.VoidState()
-			*err = nil
-		}
+	if err != nil && IsNotFound(*err) {
+		log.Println("[DEBUG] Object does not exist, voiding resource and nullifying error")
+		sync.VoidState()
+		*err = nil
 	}
 }
 
@@ -145,6 +162,11 @@ func LoadBalancerResourceGet(s BaseCrud, workReq *baremetal.WorkRequest) (id str
 
 func LoadBalancerWaitForWorkRequest(client *baremetal.Client, d *schema.ResourceData, wr *baremetal.WorkRequest) error {
 	var e error
+	ctx := client.Context()
+	timeoutCtx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+	client = client.WithContext(timeoutCtx)
+
 	stateConf := &resource.StateChangeConf{
 		Pending: []string{
 			baremetal.ResourceWaitingForWorkRequest,
@@ -157,13 +179,21 @@ func LoadBalancerWaitForWorkRequest(client *baremetal.Client, d *schema.Resource
 			baremetal.ResourceFailed,
 		},
 		Refresh: func() (interface{}, string, error) {
-			wr, e = client.GetWorkRequest(wr.ID, nil)
-			return wr, wr.State, e
+			updatedWorkReq, err := client.GetWorkRequest(wr.ID, nil)
+			if err != nil {
+				if IsRetryable(err) {
+					log.Printf("[DEBUG] crud.LoadBalancerWaitForWorkRequest: transient error, polling again: %v", err)
+					return wr, wr.State, nil
+				}
+				return nil, "", err
+			}
+			wr = updatedWorkReq
+			return wr, wr.State, nil
 		},
 		Timeout: d.Timeout(schema.TimeoutCreate),
 	}
 
-	if _, e = stateConf.WaitForState(); e != nil {
+	if _, e = waitForState(ctx, stateConf); e != nil {
 		return e
 	}
 	if wr.State == baremetal.ResourceFailed {
@@ -196,9 +226,11 @@ func CreateDBSystemResource(d *schema.ResourceData, sync ResourceCreator) (e err
 
 	d.SetId(sync.ID())
 	sync.SetData()
+	recordETag(sync)
 
 	if ew, waitOK := sync.(ExtraWaitPostCreateDelete); waitOK {
-		time.Sleep(ew.ExtraWaitPostCreateDelete())
+		// Stop waiting if interrupted; the operation itself has completed.
+		sleep(sync, ew.ExtraWaitPostCreateDelete())
 	}
 
 	return
@@ -215,9 +247,12 @@ func CreateResource(d *schema.ResourceData, sync ResourceCreator) (e error) {
 	if stateful, ok := sync.(StatefullyCreatedResource); ok {
 		e = waitForStateRefresh(stateful, d.Timeout(schema.TimeoutCreate), stateful.CreatedPending(), stateful.CreatedTarget())
 
+		if retrier, ok := sync.(WorkRequestRetrier); ok && stateful.State() == baremetal.WorkRequestFailed {
+			e = retryFailedWorkRequest(d, retrier)
+		}
+
 		if stateful.State() == baremetal.WorkRequestFailed {
 			// Remove resource from state if asynchronous work request has failed so that it is recreated on next apply
-			// TODO: automatic retry on WorkRequestFailed
 			sync.VoidState()
 			return
 		}
@@ -225,14 +260,61 @@ func CreateResource(d *schema.ResourceData, sync ResourceCreator) (e error) {
 
 	d.SetId(sync.ID())
 	sync.SetData()
+	recordETag(sync)
 
 	if ew, waitOK := sync.(ExtraWaitPostCreateDelete); waitOK {
-		time.Sleep(ew.ExtraWaitPostCreateDelete())
+		// Stop waiting if interrupted; the operation itself has completed.
+		sleep(sync, ew.ExtraWaitPostCreateDelete())
+	}
+
+	return
+}
+
+// retryFailedWorkRequest resubmits Create() while its work request keeps
+// failing, as allowed by the resource's retry policy. If the last attempt
+// still fails, the returned error carries the work request error details.
+func retryFailedWorkRequest(d *schema.ResourceData, sync WorkRequestRetrier) (e error) {
+	policy := sync.CreateRetryPolicy()
+	retryNum := 0
+	for ; policy != nil && retryNum < policy.MaxRetries && sync.State() == baremetal.WorkRequestFailed; retryNum++ {
+		wait := policy.backoff(retryNum + 1)
+		log.Printf("[WARN] crud.retryFailedWorkRequest: %s; resubmitting in %s (retry %d of %d)",
+			describeWorkRequest(sync.LastWorkRequest()), wait, retryNum+1, policy.MaxRetries)
+		if e = sleep(sync, wait); e != nil {
+			return
+		}
+
+		if e = sync.Create(); e != nil {
+			return
+		}
+		d.SetId(sync.ID())
+		// Don't let the FAILED state of the previous attempt leak into the next wait.
+		if wr := sync.LastWorkRequest(); wr != nil {
+			d.Set("state", wr.State)
+		}
+		e = waitForStateRefresh(sync, d.Timeout(schema.TimeoutCreate), sync.CreatedPending(), sync.CreatedTarget())
 	}
 
+	if sync.State() == baremetal.WorkRequestFailed {
+		e = fmt.Errorf("Resource creation failed after %d attempt(s): %s", retryNum+1, describeWorkRequest(sync.LastWorkRequest()))
+	}
 	return
 }
 
+func describeWorkRequest(wr *baremetal.WorkRequest) string {
+	if wr == nil {
+		return "work request FAILED"
+	}
+	desc := fmt.Sprintf("work request %s %s", wr.ID, wr.State)
+	if wr.Message != "" {
+		desc += fmt.Sprintf(" (%s)", wr.Message)
+	}
+	for _, detail := range wr.ErrorDetails {
+		desc += fmt.Sprintf("; %s: %s", detail.ErrorCode, detail.Message)
+	}
+	return desc
+}
+
 func ReadResource(sync ResourceReader) (e error) {
 	if e = sync.Get(); e != nil {
 		log.Printf("ERROR IN GET: %v\n", e.Error())
@@ -241,6 +323,7 @@ func ReadResource(sync ResourceReader) (e error) {
 	}
 
 	sync.SetData()
+	recordETag(sync)
 
 	// Remove resource from state if it has been terminated so that it is recreated on next apply
 	if dr, ok := sync.(StatefullyDeletedResource); ok {
@@ -258,10 +341,12 @@ func ReadResource(sync ResourceReader) (e error) {
 func UpdateResource(d *schema.ResourceData, sync ResourceUpdater) (e error) {
 	d.Partial(true)
 	if e = sync.Update(); e != nil {
+		handlePreconditionFailedError(d, &e)
 		return
 	}
 	d.Partial(false)
 	sync.SetData()
+	recordETag(sync)
 
 	return
 }
@@ -274,6 +359,7 @@ func DeleteResource(d *schema.ResourceData, sync ResourceDeleter) (e error) {
 	if e = sync.Delete(); e != nil {
 		handleMissingResourceError(sync, &e)
 		if e != nil {
+			handlePreconditionFailedError(d, &e)
 			return
 		}
 	}
@@ -284,7 +370,8 @@ func DeleteResource(d *schema.ResourceData, sync ResourceDeleter) (e error) {
 	}
 
 	if ew, waitOK := sync.(ExtraWaitPostCreateDelete); waitOK {
-		time.Sleep(ew.ExtraWaitPostCreateDelete())
+		// Stop waiting if interrupted; the operation itself has completed.
+		sleep(sync, ew.ExtraWaitPostCreateDelete())
 	}
 
 	if e == nil {
@@ -299,6 +386,12 @@ func DeleteResource(d *schema.ResourceData, sync ResourceDeleter) (e error) {
 func stateRefreshFunc(sync StatefulResource) resource.StateRefreshFunc {
 	return func() (res interface{}, s string, e error) {
 		if e = sync.Get(); e != nil {
+			// Throttling and service errors don't tell us anything about the resource,
+			// so keep polling with the last state we saw, if any.
+			if IsRetryable(e) && sync.State() != "" {
+				log.Printf("[DEBUG] crud.stateRefreshFunc: transient error, polling again: %v", e)
+				return sync, sync.State(), nil
+			}
 			return nil, "", e
 		}
 		// We don't set all the state here, because not found errors are handled elsewhere.
@@ -310,11 +403,23 @@ func stateRefreshFunc(sync StatefulResource) resource.StateRefreshFunc {
 	}
 }
 
+// WaitForStateRefresh polls sync until its state is one of target, for
+// resources that change state outside of Create and Delete.
+func WaitForStateRefresh(sync StatefulResource, timeout time.Duration, pending, target []string) error {
+	return waitForStateRefresh(sync, timeout, pending, target)
+}
+
 // waitForStateRefresh takes a StatefulResource, a timeout duration, a list of states to treat as Pending, and a list of states to treat as Target. It uses those to wrap resource.StateChangeConf.WaitForState(). If the resource returns a missing status, it will not be treated as an error.
 //
 // sync.D.Id must be set.
 // It does not set state from that refreshed state.
 func waitForStateRefresh(sync StatefulResource, timeout time.Duration, pending, target []string) (e error) {
+	// API calls made while waiting are bounded by the timeout too, so that
+	// one that is in flight when it expires doesn't outlive the wait.
+	ctx := resourceContext(sync)
+	timeoutCtx, restore := withTimeout(sync, timeout)
+	defer restore()
+
 	// TODO: try to move this onto sync
 	stateConf := &resource.StateChangeConf{
 		Pending: pending,
@@ -323,7 +428,10 @@ func waitForStateRefresh(sync StatefulResource, timeout time.Duration, pending,
 		Timeout: timeout,
 	}
 
-	if _, e = stateConf.WaitForState(); e != nil {
+	if _, e = waitForState(ctx, stateConf); e != nil {
+		if _, isTimeout := e.(*resource.TimeoutError); !isTimeout && ctx.Err() == nil && timeoutCtx.Err() == context.DeadlineExceeded {
+			e = &resource.TimeoutError{LastError: e, LastState: sync.State(), Timeout: timeout, ExpectedState: target}
+		}
 		handleMissingResourceError(sync, &e)
 		return
 	}
@@ -335,11 +443,7 @@ func waitForStateRefresh(sync StatefulResource, timeout time.Duration, pending,
 }
 
 func FilterMissingResourceError(sync ResourceVoider, err *error) {
-	if err != nil && strings.Contains((*err).Error(), "does not exist") {
-		log.Println("[DEBUG] Object does not exist, voiding resource and nullifying error")
-		sync.VoidState()
-		*err = nil
-	}
+	handleMissingResourceError(sync, err)
 }
 
 func EqualIgnoreCaseSuppressDiff(key string, old string, new string, d *schema.ResourceData) bool {
@@ -0,0 +1,114 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package crud
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/oracle/bmcs-go-sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+// workRequestCrud fakes a resource that is created through a work request,
+// where each submission ends up in the next state listed in outcomes.
+type workRequestCrud struct {
+	BaseCrud
+	WorkRequest *baremetal.WorkRequest
+	RetryPolicy *WorkRequestRetryPolicy
+	outcomes    []string
+	submissions int
+}
+
+func (s *workRequestCrud) ID() string {
+	return s.WorkRequest.ID
+}
+
+func (s *workRequestCrud) Create() error {
+	s.submissions++
+	s.WorkRequest = &baremetal.WorkRequest{
+		ID:    fmt.Sprintf("ocid1.loadbalancerworkrequest.%d", s.submissions),
+		State: baremetal.WorkRequestAccepted,
+	}
+	return nil
+}
+
+func (s *workRequestCrud) Get() error {
+	s.WorkRequest.State = s.outcomes[s.submissions-1]
+	if s.WorkRequest.State == baremetal.WorkRequestFailed {
+		s.WorkRequest.ErrorDetails = []baremetal.WorkRequestError{
+			{ErrorCode: "INTERNAL_ERROR", Message: "backend set provisioning failed"},
+		}
+	}
+	return nil
+}
+
+func (s *workRequestCrud) SetData() {}
+
+func (s *workRequestCrud) CreatedPending() []string {
+	return []string{baremetal.WorkRequestAccepted, baremetal.WorkRequestInProgress}
+}
+
+func (s *workRequestCrud) CreatedTarget() []string {
+	return []string{baremetal.WorkRequestSucceeded, baremetal.WorkRequestFailed}
+}
+
+func (s *workRequestCrud) CreateRetryPolicy() *WorkRequestRetryPolicy {
+	return s.RetryPolicy
+}
+
+func (s *workRequestCrud) LastWorkRequest() *baremetal.WorkRequest {
+	return s.WorkRequest
+}
+
+// applyCreate runs CreateResource through schema.Resource.Apply, so that
+// ResourceData carries timeouts the way it does under Terraform.
+func applyCreate(s *workRequestCrud) error {
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"state": {Type: schema.TypeString, Computed: true},
+		},
+		Create: func(d *schema.ResourceData, m interface{}) error {
+			s.D = d
+			return CreateResource(d, s)
+		},
+	}
+	_, err := r.Apply(nil, &terraform.InstanceDiff{}, nil)
+	return err
+}
+
+func TestCreateResource_retriesFailedWorkRequest(t *testing.T) {
+	s := &workRequestCrud{
+		RetryPolicy: &WorkRequestRetryPolicy{MaxRetries: 2},
+		outcomes:    []string{baremetal.WorkRequestFailed, baremetal.WorkRequestSucceeded},
+	}
+
+	assert.Nil(t, applyCreate(s))
+	assert.Equal(t, 2, s.submissions)
+	assert.Equal(t, "ocid1.loadbalancerworkrequest.2", s.D.Id())
+}
+
+func TestCreateResource_givesUpOnFailedWorkRequest(t *testing.T) {
+	s := &workRequestCrud{
+		RetryPolicy: &WorkRequestRetryPolicy{MaxRetries: 1},
+		outcomes:    []string{baremetal.WorkRequestFailed, baremetal.WorkRequestFailed},
+	}
+
+	err := applyCreate(s)
+	assert.Equal(t, 2, s.submissions)
+	assert.Equal(t, "", s.D.Id())
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "ocid1.loadbalancerworkrequest.2")
+		assert.Contains(t, err.Error(), "INTERNAL_ERROR: backend set provisioning failed")
+	}
+}
+
+func TestWorkRequestRetryPolicy_backoff(t *testing.T) {
+	p := &WorkRequestRetryPolicy{Backoff: 10, MaxBackoff: 35}
+	assert.EqualValues(t, 10, p.backoff(1))
+	assert.EqualValues(t, 20, p.backoff(2))
+	assert.EqualValues(t, 35, p.backoff(3))
+	assert.EqualValues(t, 35, p.backoff(10))
+}
@@ -3,6 +3,7 @@
 package crud
 
 import (
+	"context"
 	"time"
 
 	"github.com/oracle/bmcs-go-sdk"
@@ -73,6 +74,14 @@ type ExtraWaitPostCreateDelete interface {
 	ExtraWaitPostCreateDelete() time.Duration
 }
 
+// ContextualResource runs its API calls under a context, so that crud can
+// stop them when Terraform is interrupted or an operation times out.
+// BaseCrud implements it through its Client.
+type ContextualResource interface {
+	Context() context.Context
+	SetContext(ctx context.Context)
+}
+
 type StatefulResource interface {
 	ResourceReader
 	State() string
@@ -97,6 +106,19 @@ type StatefullyDeletedResource interface {
 	DeletedTarget() []string
 }
 
+// WorkRequestRetrier is implemented by resources that create through an
+// asynchronous work request which may be resubmitted if it fails.
+type WorkRequestRetrier interface {
+	StatefullyCreatedResource
+	Create() error
+	ID() string
+	// CreateRetryPolicy bounds how often CreateResource resubmits Create()
+	// after its work request FAILED. A nil policy disables resubmission.
+	CreateRetryPolicy() *WorkRequestRetryPolicy
+	// LastWorkRequest is the work request most recently submitted by Create().
+	LastWorkRequest() *baremetal.WorkRequest
+}
+
 type IdentitySync struct{}
 
 func (s *IdentitySync) CreatedPending() []string {
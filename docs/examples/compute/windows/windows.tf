@@ -6,6 +6,12 @@ variable "region" {}
 
 variable "compartment_ocid" {}
 
+// The instance's password is encrypted with this key. Decrypt it with e.g.
+// terraform output EncryptedPassword | base64 --decode | gpg --decrypt
+variable "pgp_key" {
+  description = "A base64 encoded PGP public key, or keybase:<username>"
+}
+
 variable "AD" {
   default = "1"
 }
@@ -57,6 +63,7 @@ resource "oci_core_subnet" "ExampleSubnet" {
 
 data "oci_core_instance_credentials" "InstanceCredentials" {
   instance_id = "${oci_core_instance.TFInstance.id}"
+  pgp_key = "${var.pgp_key}"
 }
 
 resource "oci_core_instance" "TFInstance" {
@@ -74,8 +81,8 @@ output "Username" {
   value = ["${data.oci_core_instance_credentials.InstanceCredentials.username}"]
 }
 
-output "Password" {
-  value = ["${data.oci_core_instance_credentials.InstanceCredentials.password}"]
+output "EncryptedPassword" {
+  value = "${data.oci_core_instance_credentials.InstanceCredentials.encrypted_password}"
 }
 
 output "InstancePublicIP" {
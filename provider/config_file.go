@@ -0,0 +1,96 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"fmt"
+
+	"github.com/go-ini/ini"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mitchellh/go-homedir"
+)
+
+const defaultConfigFilePath = "~/.oci/config"
+
+// Provider arguments that can be read from an OCI CLI config file, and the
+// config file key that supplies each of them.
+var configFileKeys = map[string]string{
+	"tenancy_ocid":         "tenancy",
+	"user_ocid":            "user",
+	"fingerprint":          "fingerprint",
+	"private_key_path":     "key_file",
+	"private_key_password": "pass_phrase",
+	"region":               "region",
+}
+
+// configFileProfile is a named profile in an OCI CLI config file. Like the
+// CLI, keys missing from the profile are looked up in the DEFAULT profile.
+type configFileProfile struct {
+	name     string
+	path     string
+	section  *ini.Section
+	defaults *ini.Section
+}
+
+func loadConfigFileProfile(path, name string) (*configFileProfile, error) {
+	expandedPath, err := homedir.Expand(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := ini.Load(expandedPath)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read config file %s: %s", path, err)
+	}
+
+	section, err := file.GetSection(name)
+	if err != nil {
+		return nil, fmt.Errorf("Profile %q was not found in config file %s", name, path)
+	}
+
+	return &configFileProfile{
+		name:     name,
+		path:     path,
+		section:  section,
+		defaults: file.Section(ini.DEFAULT_SECTION),
+	}, nil
+}
+
+func (p *configFileProfile) get(key string) string {
+	if p.section.HasKey(key) {
+		return p.section.Key(key).String()
+	}
+	return p.defaults.Key(key).String()
+}
+
+// getProviderSetting returns the provider argument attr when it is set,
+// otherwise the matching value from the config file profile, if any.
+func getProviderSetting(d *schema.ResourceData, profile *configFileProfile, attr string) string {
+	if v, ok := d.Get(attr).(string); ok && v != "" {
+		return v
+	}
+	if profile == nil {
+		return ""
+	}
+
+	v := profile.get(configFileKeys[attr])
+	if attr == "private_key_path" && v != "" {
+		if expanded, err := homedir.Expand(v); err == nil {
+			v = expanded
+		}
+	}
+	return v
+}
+
+// getRequiredProviderSetting is getProviderSetting, but fails when neither
+// the provider argument nor the config file profile supplies a value.
+func getRequiredProviderSetting(d *schema.ResourceData, profile *configFileProfile, attr string) (string, error) {
+	if v := getProviderSetting(d, profile, attr); v != "" {
+		return v, nil
+	}
+	if profile == nil {
+		return "", fmt.Errorf("%s is required", attr)
+	}
+	return "", fmt.Errorf("%s is required: it is not set on the provider and %q is missing from profile %q in %s",
+		attr, configFileKeys[attr], profile.name, profile.path)
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestConfigFile(t *testing.T) (dir, configPath string) {
+	dir, err := ioutil.TempDir("", "oci-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyPath := filepath.Join(dir, "oci_api_key.pem")
+	if err = ioutil.WriteFile(keyPath, []byte(testPrivateKey), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	configPath = filepath.Join(dir, "config")
+	config := `[DEFAULT]
+tenancy=` + testTenancyOCID + `
+region=us-ashburn-1
+
+[dev]
+user=` + testUserOCID + `
+fingerprint=` + testKeyFingerPrint + `
+key_file=` + keyPath + `
+pass_phrase=password
+
+[broken]
+user=` + testUserOCID + `
+key_file=` + keyPath + `
+`
+	if err = ioutil.WriteFile(configPath, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func newProviderResourceData() *schema.ResourceData {
+	r := &schema.Resource{
+		Schema: schemaMap(),
+	}
+	d := r.Data(nil)
+	d.SetId("tenancy_ocid")
+	return d
+}
+
+func TestProviderConfig_configFileProfile(t *testing.T) {
+	dir, configPath := writeTestConfigFile(t)
+	defer os.RemoveAll(dir)
+
+	d := newProviderResourceData()
+	d.Set("config_file_path", configPath)
+	d.Set("config_file_profile", "dev")
+
+	profile, err := loadConfigFileProfile(configPath, "dev")
+	assert.Nil(t, err)
+	// Values missing from the profile come from DEFAULT.
+	assert.Equal(t, testTenancyOCID, getProviderSetting(d, profile, "tenancy_ocid"))
+	assert.Equal(t, "us-ashburn-1", getProviderSetting(d, profile, "region"))
+
+	// Explicit provider arguments win over the profile.
+	d.Set("region", "us-phoenix-1")
+	assert.Equal(t, "us-phoenix-1", getProviderSetting(d, profile, "region"))
+
+	client, err := ProviderConfig(d)
+	assert.Nil(t, err)
+	_, ok := client.(*OracleClients)
+	assert.True(t, ok)
+}
+
+func TestProviderConfig_configFileProfileMissingField(t *testing.T) {
+	dir, configPath := writeTestConfigFile(t)
+	defer os.RemoveAll(dir)
+
+	d := newProviderResourceData()
+	d.Set("config_file_path", configPath)
+	d.Set("config_file_profile", "broken")
+
+	_, err := ProviderConfig(d)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "fingerprint")
+		assert.Contains(t, err.Error(), `profile "broken"`)
+		assert.Contains(t, err.Error(), configPath)
+	}
+
+	d.Set("config_file_profile", "missing")
+	_, err = ProviderConfig(d)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `Profile "missing" was not found`)
+	}
+}
@@ -3,7 +3,15 @@
 package provider
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"hash"
+	"io"
+	"log"
+	"os"
+	"regexp"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
@@ -24,6 +32,18 @@ func ConsoleHistoryDataDatasource() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			// Pages through the rest of the capture, from offset on,
+			// length bytes at a time.
+			"follow_pages": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"grep": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp,
+			},
 			"length": {
 				Type:     schema.TypeInt,
 				Optional: true,
@@ -43,16 +63,38 @@ func ConsoleHistoryDataDatasource() *schema.Resource {
 					return
 				},
 			},
+			"matches": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 			"offset": {
 				Type:     schema.TypeInt,
 				Optional: true,
 			},
+			// When set, the data is written to this file instead of to data,
+			// which keeps large captures out of the state.
+			"output_path": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"sha256": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
 		},
 	}
 }
 
 func readConsoleHistoryData(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &ConsoleHistoryDataDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
@@ -60,11 +102,24 @@ func readConsoleHistoryData(d *schema.ResourceData, m interface{}) (e error) {
 	return crud.ReadResource(reader)
 }
 
+func validateRegexp(i interface{}, k string) (s []string, es []error) {
+	if _, err := regexp.Compile(i.(string)); err != nil {
+		es = append(es, fmt.Errorf("%s is not a valid regular expression: %s", k, err))
+	}
+	return
+}
+
 type ConsoleHistoryDataDatasourceCrud struct {
 	crud.BaseCrud
 	ConsoleHistoryData *baremetal.ConsoleHistoryData
+	Size               int
+	SHA256             string
+	Matches            []string
 }
 
+// Get reads the data, page by page with follow_pages, into data or the file
+// at output_path. Its size, SHA-256 and the lines that match grep are
+// computed as it is read.
 func (res *ConsoleHistoryDataDatasourceCrud) Get() (e error) {
 	id := res.D.Get("console_history_id").(string)
 
@@ -72,12 +127,108 @@ func (res *ConsoleHistoryDataDatasourceCrud) Get() (e error) {
 	opts.Length = uint64(res.D.Get("length").(int))
 	opts.Offset = uint64(res.D.Get("offset").(int))
 
-	res.ConsoleHistoryData, e = res.Client.ShowConsoleHistoryData(id, opts)
+	out := &consoleHistoryDataWriter{hash: sha256.New()}
+	if outputPath := res.D.Get("output_path").(string); outputPath != "" {
+		var file *os.File
+		if file, e = os.Create(outputPath); e != nil {
+			return
+		}
+		defer func() {
+			if err := file.Close(); e == nil {
+				e = err
+			}
+			if e != nil {
+				os.Remove(outputPath)
+			}
+		}()
+		out.dest = file
+	} else {
+		out.dest = &bytes.Buffer{}
+	}
+	if pattern := res.D.Get("grep").(string); pattern != "" {
+		out.grep = regexp.MustCompile(pattern)
+	}
+
+	for {
+		var page *baremetal.ConsoleHistoryData
+		if page, e = res.Client.ShowConsoleHistoryData(id, opts); e != nil {
+			return
+		}
+		if _, e = io.WriteString(out, page.Data); e != nil {
+			return
+		}
+		if !res.D.Get("follow_pages").(bool) || page.BytesRemaining <= 0 || len(page.Data) == 0 {
+			break
+		}
+		opts.Offset += uint64(len(page.Data))
+		log.Printf("[DEBUG] Read %d bytes of console history %q, %d bytes remaining", out.size, id, page.BytesRemaining)
+	}
+	out.flushLine()
 
+	res.ConsoleHistoryData = &baremetal.ConsoleHistoryData{}
+	if buf, ok := out.dest.(*bytes.Buffer); ok {
+		res.ConsoleHistoryData.Data = buf.String()
+	}
+	res.Size = out.size
+	res.SHA256 = hex.EncodeToString(out.hash.Sum(nil))
+	res.Matches = out.matches
 	return
 }
 
 func (res *ConsoleHistoryDataDatasourceCrud) SetData() {
 	res.D.SetId(time.Now().UTC().String())
 	res.D.Set("data", res.ConsoleHistoryData.Data)
+	res.D.Set("matches", res.Matches)
+	res.D.Set("sha256", res.SHA256)
+	res.D.Set("size", res.Size)
+}
+
+// consoleHistoryDataWriter writes console history data to dest, while it
+// hashes and counts it, and collects the lines that match grep.
+type consoleHistoryDataWriter struct {
+	dest    io.Writer
+	hash    hash.Hash
+	size    int
+	grep    *regexp.Regexp
+	line    []byte
+	matches []string
+}
+
+func (w *consoleHistoryDataWriter) Write(p []byte) (n int, e error) {
+	if n, e = w.dest.Write(p); e != nil {
+		return
+	}
+	w.hash.Write(p)
+	w.size += n
+	if w.grep == nil {
+		return
+	}
+
+	// A line can span pages, so the last, partial line of p is kept until
+	// the rest of it is written.
+	w.line = append(w.line, p...)
+	for {
+		i := bytes.IndexByte(w.line, '\n')
+		if i < 0 {
+			break
+		}
+		w.matchLine(w.line[:i])
+		w.line = w.line[i+1:]
+	}
+	return
+}
+
+// flushLine matches the last line, if the data doesn't end with a newline.
+func (w *consoleHistoryDataWriter) flushLine() {
+	if w.grep != nil && len(w.line) > 0 {
+		w.matchLine(w.line)
+		w.line = nil
+	}
+}
+
+func (w *consoleHistoryDataWriter) matchLine(line []byte) {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if w.grep.Match(line) {
+		w.matches = append(w.matches, string(line))
+	}
 }
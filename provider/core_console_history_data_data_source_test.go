@@ -3,12 +3,21 @@
 package provider
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	baremetal "github.com/oracle/bmcs-go-sdk"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -54,6 +63,81 @@ func (s *CoreConsoleHistoryDataDatasourceTestSuite) TestAccDatasourceCoreConsole
 	)
 }
 
+const testConsoleHistory = "Booting\r\ncloud-init: modules:config start\nstarting sshd\ncloud-init: modules:final done"
+
+// consoleHistoryStandIn serves testConsoleHistory at the offset and length
+// requested, as the API does.
+func consoleHistoryStandIn(w http.ResponseWriter, r *http.Request) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	length, _ := strconv.Atoi(r.URL.Query().Get("length"))
+	end := len(testConsoleHistory)
+	if length > 0 && offset+length < end {
+		end = offset + length
+	}
+	w.Header().Set("content-type", "application/octet-stream")
+	w.Header().Set("opc-bytes-remaining", strconv.Itoa(len(testConsoleHistory)-end))
+	w.Write([]byte(testConsoleHistory[offset:end]))
+}
+
+func readConsoleHistoryDataFromStandIn(t *testing.T, config map[string]interface{}) (d *schema.ResourceData, err error) {
+	clients, closeServer, err := newStandInClient(consoleHistoryStandIn, func(d *schema.ResourceData) {})
+	if err != nil {
+		return
+	}
+	defer closeServer()
+
+	d = ConsoleHistoryDataDatasource().Data(nil)
+	d.Set("console_history_id", "ocid1.consolehistory.oc1..standin")
+	for k, v := range config {
+		d.Set(k, v)
+	}
+	err = readConsoleHistoryData(d, clients)
+	return
+}
+
+func TestConsoleHistoryData_singlePage(t *testing.T) {
+	d, err := readConsoleHistoryDataFromStandIn(t, map[string]interface{}{"length": 10})
+	if assert.Nil(t, err) {
+		assert.Equal(t, "Booting\r\nc", d.Get("data"))
+		assert.Equal(t, 10, d.Get("size"))
+	}
+}
+
+func TestConsoleHistoryData_followPagesToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "console-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	outputPath := filepath.Join(dir, "boot.log")
+
+	// The pages split lines, which grep still matches whole.
+	d, err := readConsoleHistoryDataFromStandIn(t, map[string]interface{}{
+		"length":       10,
+		"follow_pages": true,
+		"output_path":  outputPath,
+		"grep":         "^cloud-init: .* (start|done)$",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	written, err := ioutil.ReadFile(outputPath)
+	if assert.Nil(t, err) {
+		assert.Equal(t, testConsoleHistory, string(written))
+	}
+	sum := sha256.Sum256([]byte(testConsoleHistory))
+	assert.Equal(t, hex.EncodeToString(sum[:]), d.Get("sha256"))
+	assert.Equal(t, len(testConsoleHistory), d.Get("size"))
+	assert.Equal(t, "", d.Get("data"))
+	assert.Equal(t, []interface{}{"cloud-init: modules:config start", "cloud-init: modules:final done"}, d.Get("matches"))
+}
+
+func TestConsoleHistoryData_invalidGrep(t *testing.T) {
+	_, errs := validateRegexp("cloud-init: (", "grep")
+	assert.Len(t, errs, 1)
+}
+
 func TestDatasourceCoreConsoleHistoryTestSuite(t *testing.T) {
 	suite.Run(t, new(CoreConsoleHistoryDataDatasourceTestSuite))
 }
@@ -54,7 +54,10 @@ func ConsoleHistoryResource() *schema.Resource {
 }
 
 func createConsoleHistory(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	ichCrud := &ConsoleHistoryResourceCrud{}
 	ichCrud.D = d
 	ichCrud.Client = client.client
@@ -62,7 +65,10 @@ func createConsoleHistory(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readConsoleHistory(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	ichCrud := &ConsoleHistoryResourceCrud{}
 	ichCrud.D = d
 	ichCrud.Client = client.client
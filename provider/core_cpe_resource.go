@@ -20,6 +20,7 @@ func CpeResource() *schema.Resource {
 		Update:   updateCpe,
 		Delete:   deleteCpe,
 		Schema: map[string]*schema.Schema{
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -49,7 +50,10 @@ func CpeResource() *schema.Resource {
 }
 
 func createCpe(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &CpeResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -57,7 +61,10 @@ func createCpe(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readCpe(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &CpeResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -65,7 +72,10 @@ func readCpe(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func updateCpe(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	crd := &CpeResourceCrud{}
 	crd.D = d
 	crd.Client = client.client
@@ -73,7 +83,10 @@ func updateCpe(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteCpe(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &CpeResourceCrud{}
 	sync.D = d
 	sync.Client = client.clientWithoutNotFoundRetries
@@ -113,6 +126,7 @@ func (s *CpeResourceCrud) Get() (e error) {
 
 func (s *CpeResourceCrud) Update() (e error) {
 	opts := &baremetal.IfMatchDisplayNameOptions{}
+	opts.IfMatch = s.IfMatch()
 	compartmentID := s.D.Get("compartment_id").(string)
 	displayName, ok := s.D.GetOk("display_name")
 	if ok {
@@ -131,5 +145,5 @@ func (s *CpeResourceCrud) SetData() {
 }
 
 func (s *CpeResourceCrud) Delete() (e error) {
-	return s.Client.DeleteCpe(s.D.Id(), nil)
+	return s.Client.DeleteCpe(s.D.Id(), s.IfMatchOptions())
 }
@@ -39,9 +39,13 @@ func CpeDatasource() *schema.Resource {
 }
 
 func readCpeList(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &CPEDatasourceCrud{}
 	reader.D = d
-	reader.Client = m.(*OracleClients).client
+	reader.Client = client.client
 	return crud.ReadResource(reader)
 
 }
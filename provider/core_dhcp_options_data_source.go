@@ -43,7 +43,10 @@ func DHCPOptionsDatasource() *schema.Resource {
 }
 
 func readDHCPOptionsList(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &DHCPOptionsDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
@@ -25,6 +25,7 @@ func DefaultDHCPOptionsResource() *schema.Resource {
 				Computed: true,
 				Optional: true,
 			},
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -93,6 +94,7 @@ func DHCPOptionsResource() *schema.Resource {
 				Computed: true,
 				Optional: true,
 			},
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -141,7 +143,10 @@ func DHCPOptionsResource() *schema.Resource {
 }
 
 func createDHCPOptions(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	crd := &DHCPOptionsResourceCrud{}
 	crd.D = d
 	crd.Client = client.client
@@ -150,14 +155,20 @@ func createDHCPOptions(d *schema.ResourceData, m interface{}) (e error) {
 
 func readDHCPOptions(d *schema.ResourceData, m interface{}) (e error) {
 	crd := &DHCPOptionsResourceCrud{}
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	crd.D = d
 	crd.Client = client.client
 	return crud.ReadResource(crd)
 }
 
 func updateDHCPOptions(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	crd := &DHCPOptionsResourceCrud{}
 	crd.D = d
 	crd.Client = client.client
@@ -165,7 +176,10 @@ func updateDHCPOptions(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteDHCPOptions(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	crd := &DHCPOptionsResourceCrud{}
 	crd.D = d
 	crd.Client = client.clientWithoutNotFoundRetries
@@ -241,6 +255,7 @@ func (s *DHCPOptionsResourceCrud) Get() (e error) {
 
 func (s *DHCPOptionsResourceCrud) Update() (e error) {
 	opts := &baremetal.UpdateDHCPDNSOptions{}
+	opts.IfMatch = s.IfMatch()
 	opts.Options = s.buildEntities()
 
 	s.Res, e = s.Client.UpdateDHCPOptions(s.D.Id(), opts)
@@ -277,6 +292,7 @@ func (s *DHCPOptionsResourceCrud) reset() (e error) {
 			},
 		},
 	}
+	opts.IfMatch = s.IfMatch()
 
 	_, e = s.Client.UpdateDHCPOptions(s.D.Id(), opts)
 	return
@@ -291,7 +307,7 @@ func (s *DHCPOptionsResourceCrud) Delete() (e error) {
 		return
 	}
 
-	return s.Client.DeleteDHCPOptions(s.D.Id(), nil)
+	return s.Client.DeleteDHCPOptions(s.D.Id(), s.IfMatchOptions())
 }
 
 func (s *DHCPOptionsResourceCrud) buildEntities() (entities []baremetal.DHCPDNSOption) {
@@ -35,6 +35,7 @@ func DrgAttachmentResource() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -57,7 +58,10 @@ func DrgAttachmentResource() *schema.Resource {
 }
 
 func createDrgAttachment(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DrgAttachmentResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -65,7 +69,10 @@ func createDrgAttachment(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readDrgAttachment(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DrgAttachmentResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -73,7 +80,10 @@ func readDrgAttachment(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func updateDrgAttachment(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DrgAttachmentResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -81,7 +91,10 @@ func updateDrgAttachment(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteDrgAttachment(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DrgAttachmentResourceCrud{}
 	sync.D = d
 	sync.Client = client.clientWithoutNotFoundRetries
@@ -141,6 +154,7 @@ func (s *DrgAttachmentResourceCrud) Get() (e error) {
 
 func (s *DrgAttachmentResourceCrud) Update() (e error) {
 	opts := &baremetal.IfMatchDisplayNameOptions{}
+	opts.IfMatch = s.IfMatch()
 
 	if displayName, ok := s.D.GetOk("display_name"); ok {
 		opts.DisplayName = displayName.(string)
@@ -160,5 +174,5 @@ func (s *DrgAttachmentResourceCrud) SetData() {
 }
 
 func (s *DrgAttachmentResourceCrud) Delete() (e error) {
-	return s.Client.DeleteDrgAttachment(s.D.Id(), nil)
+	return s.Client.DeleteDrgAttachment(s.D.Id(), s.IfMatchOptions())
 }
@@ -47,7 +47,10 @@ func DrgAttachmentDatasource() *schema.Resource {
 }
 
 func readDrgAttachments(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DrgAttachmentDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -30,6 +30,7 @@ func DrgResource() *schema.Resource {
 				Computed: true,
 				Optional: true,
 			},
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -47,7 +48,10 @@ func DrgResource() *schema.Resource {
 }
 
 func createDrg(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DrgResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -55,7 +59,10 @@ func createDrg(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readDrg(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DrgResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -63,7 +70,10 @@ func readDrg(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func updateDrg(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DrgResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -71,7 +81,10 @@ func updateDrg(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteDrg(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DrgResourceCrud{}
 	sync.D = d
 	sync.Client = client.clientWithoutNotFoundRetries
@@ -126,6 +139,7 @@ func (s *DrgResourceCrud) Get() (e error) {
 
 func (s *DrgResourceCrud) Update() (e error) {
 	opts := &baremetal.IfMatchDisplayNameOptions{}
+	opts.IfMatch = s.IfMatch()
 
 	if displayName, ok := s.D.GetOk("display_name"); ok {
 		opts.DisplayName = displayName.(string)
@@ -143,5 +157,5 @@ func (s *DrgResourceCrud) SetData() {
 }
 
 func (s *DrgResourceCrud) Delete() (e error) {
-	return s.Client.DeleteDrg(s.D.Id(), nil)
+	return s.Client.DeleteDrg(s.D.Id(), s.IfMatchOptions())
 }
@@ -40,7 +40,10 @@ func DrgDatasource() *schema.Resource {
 }
 
 func readDrgs(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DrgDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -0,0 +1,248 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/oracle/bmcs-go-sdk"
+
+	"github.com/oracle/terraform-provider-oci/crud"
+	"github.com/oracle/terraform-provider-oci/options"
+)
+
+// ImageLookupDatasource selects a single available image, e.g. the newest
+// Oracle Linux 7.4 image, so that configurations don't have to pin image
+// OCIDs per region.
+func ImageLookupDatasource() *schema.Resource {
+	return &schema.Resource{
+		Read: readImageLookup,
+		Schema: map[string]*schema.Schema{
+			"compartment_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"most_recent": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"name_pattern": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp,
+			},
+			"operating_system": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"operating_system_version": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"shape_compatible": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// Computed
+			"base_image_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"create_image_allowed": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"display_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"launch_mode": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"time_created": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func readImageLookup(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
+	sync := &ImageLookupDatasourceCrud{}
+	sync.D = d
+	sync.Client = client.client
+	return crud.ReadResource(sync)
+}
+
+type ImageLookupDatasourceCrud struct {
+	crud.BaseCrud
+	Res *baremetal.Image
+}
+
+// Get lists the available images that match the arguments, and picks the
+// only one, or the newest one with most_recent.
+func (s *ImageLookupDatasourceCrud) Get() (e error) {
+	compartmentID := s.D.Get("compartment_id").(string)
+
+	opts := &baremetal.ListImagesOptions{}
+	if val, ok := s.D.GetOk("operating_system"); ok {
+		opts.OperatingSystem = val.(string)
+	}
+	if val, ok := s.D.GetOk("operating_system_version"); ok {
+		opts.OperatingSystemVersion = val.(string)
+	}
+	if val, ok := s.D.GetOk("shape_compatible"); ok {
+		opts.Shape = val.(string)
+	}
+	var namePattern *regexp.Regexp
+	if val, ok := s.D.GetOk("name_pattern"); ok {
+		if namePattern, e = regexp.Compile(val.(string)); e != nil {
+			return
+		}
+	}
+
+	var images []baremetal.Image
+	for {
+		var list *baremetal.ListImages
+		if list, e = s.Client.ListImages(compartmentID, opts); e != nil {
+			return
+		}
+		for _, image := range list.Images {
+			if image.State != baremetal.ResourceAvailable {
+				continue
+			}
+			if namePattern != nil && !namePattern.MatchString(image.DisplayName) {
+				continue
+			}
+			images = append(images, image)
+		}
+		if hasNextPage := options.SetNextPageOption(list.NextPage, &opts.ListOptions.PageListOptions); !hasNextPage {
+			break
+		}
+	}
+
+	switch {
+	case len(images) == 0:
+		return fmt.Errorf("No available image in compartment %s matches the arguments", compartmentID)
+	case len(images) > 1 && !s.D.Get("most_recent").(bool):
+		names := make([]string, len(images))
+		for i, image := range images {
+			names[i] = image.DisplayName
+		}
+		return fmt.Errorf("%d images match the arguments, set most_recent to pick the newest one or narrow them down: %s",
+			len(images), strings.Join(names, ", "))
+	}
+
+	sort.SliceStable(images, func(i, j int) bool {
+		return compareImages(&images[i], &images[j]) > 0
+	})
+	s.Res = &images[0]
+	return
+}
+
+func (s *ImageLookupDatasourceCrud) SetData() {
+	if s.Res == nil {
+		return
+	}
+	s.D.SetId(s.Res.ID)
+	s.D.Set("base_image_id", s.Res.BaseImageID)
+	s.D.Set("create_image_allowed", s.Res.CreateImageAllowed)
+	s.D.Set("display_name", s.Res.DisplayName)
+	s.D.Set("launch_mode", s.Res.LaunchMode)
+	s.D.Set("operating_system", s.Res.OperatingSystem)
+	s.D.Set("operating_system_version", s.Res.OperatingSystemVersion)
+	s.D.Set("state", s.Res.State)
+	s.D.Set("time_created", s.Res.TimeCreated.String())
+}
+
+// imageDisplayNamePattern matches the date-stamped display names of
+// Oracle-provided images, e.g. "Oracle-Linux-7.4-2017.12.18-0": an optional
+// version, a release date and a build number.
+var imageDisplayNamePattern = regexp.MustCompile(`^.*?(?:-(\d+(?:\.\d+)*))?-(\d{4}\.\d{2}\.\d{2})-(\d+)$`)
+
+// imageRelease is what an image's display name says about its release.
+type imageRelease struct {
+	version []int
+	date    string
+	build   int
+}
+
+func parseImageRelease(displayName string) (release imageRelease, ok bool) {
+	match := imageDisplayNamePattern.FindStringSubmatch(displayName)
+	if match == nil {
+		return
+	}
+	if match[1] != "" {
+		for _, part := range strings.Split(match[1], ".") {
+			n, _ := strconv.Atoi(part)
+			release.version = append(release.version, n)
+		}
+	}
+	// yyyy.mm.dd dates order as strings.
+	release.date = match[2]
+	release.build, _ = strconv.Atoi(match[3])
+	return release, true
+}
+
+// compareImages returns a positive number if a is newer than b, a negative
+// one if it is older, and 0 if they can't be told apart. Images are ordered
+// by the version in their display names, compared numerically, then by
+// release date and build number. Images whose names don't follow that form
+// are older than those that do, and are ordered by time_created.
+func compareImages(a, b *baremetal.Image) int {
+	releaseA, okA := parseImageRelease(a.DisplayName)
+	releaseB, okB := parseImageRelease(b.DisplayName)
+	switch {
+	case okA && !okB:
+		return 1
+	case !okA && okB:
+		return -1
+	case okA && okB:
+		for i := 0; i < len(releaseA.version) || i < len(releaseB.version); i++ {
+			if i >= len(releaseA.version) {
+				return -1
+			}
+			if i >= len(releaseB.version) {
+				return 1
+			}
+			if c := releaseA.version[i] - releaseB.version[i]; c != 0 {
+				return c
+			}
+		}
+		if c := strings.Compare(releaseA.date, releaseB.date); c != 0 {
+			return c
+		}
+		if c := releaseA.build - releaseB.build; c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case a.TimeCreated.After(b.TimeCreated.Time):
+		return 1
+	case a.TimeCreated.Before(b.TimeCreated.Time):
+		return -1
+	}
+	return 0
+}
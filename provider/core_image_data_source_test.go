@@ -0,0 +1,180 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/oracle/bmcs-go-sdk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DatasourceCoreImageLookupTestSuite struct {
+	suite.Suite
+	Providers    map[string]terraform.ResourceProvider
+	Config       string
+	ResourceName string
+}
+
+func (s *DatasourceCoreImageLookupTestSuite) SetupTest() {
+	s.Providers = testAccProviders
+	s.Config = testProviderConfig()
+	s.ResourceName = "data.oci_core_image.t"
+}
+
+func (s *DatasourceCoreImageLookupTestSuite) TestAccImageLookup_mostRecent() {
+	resource.Test(s.T(), resource.TestCase{
+		PreventPostDestroyRefresh: true,
+		Providers:                 s.Providers,
+		Steps: []resource.TestStep{
+			{
+				Config: s.Config + `
+				data "oci_core_image" "t" {
+					compartment_id = "${var.compartment_id}"
+					operating_system = "Oracle Linux"
+					operating_system_version = "7.4"
+					shape_compatible = "VM.Standard1.1"
+					name_pattern = "^Oracle-Linux-7\\.4-\\d{4}\\."
+					most_recent = true
+				}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(s.ResourceName, "id"),
+					resource.TestMatchResourceAttr(s.ResourceName, "display_name", regexp.MustCompile(`^Oracle-Linux-7\.4-`)),
+					resource.TestCheckResourceAttr(s.ResourceName, "state", baremetal.ResourceAvailable),
+				),
+			},
+		},
+	})
+}
+
+func TestDatasourceCoreImageLookupTestSuite(t *testing.T) {
+	suite.Run(t, new(DatasourceCoreImageLookupTestSuite))
+}
+
+func TestCompareImages(t *testing.T) {
+	image := func(displayName, timeCreated string) *baremetal.Image {
+		res := &baremetal.Image{DisplayName: displayName}
+		if err := res.TimeCreated.UnmarshalJSON([]byte(`"` + timeCreated + `"`)); err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	newerThan := [][2]*baremetal.Image{
+		// Versions are compared numerically, before the release date.
+		{image("Oracle-Linux-7.10-2017.01.01-0", "2017-01-01T00:00:00Z"), image("Oracle-Linux-7.4-2017.12.18-0", "2017-12-18T00:00:00Z")},
+		{image("Oracle-Linux-7.4-2017.12.18-0", "2017-12-18T00:00:00Z"), image("Oracle-Linux-6.9-2018.01.10-0", "2018-01-10T00:00:00Z")},
+		{image("Oracle-Linux-7.4.1-2017.01.01-0", "2017-01-01T00:00:00Z"), image("Oracle-Linux-7.4-2017.12.18-0", "2017-12-18T00:00:00Z")},
+		// Then the release date and the build number.
+		{image("Oracle-Linux-7.4-2018.01.10-0", "2018-01-10T00:00:00Z"), image("Oracle-Linux-7.4-2017.12.18-1", "2017-12-18T00:00:00Z")},
+		{image("Oracle-Linux-7.4-2017.12.18-10", "2017-12-18T00:00:00Z"), image("Oracle-Linux-7.4-2017.12.18-9", "2017-12-19T00:00:00Z")},
+		{image("Windows-Server-2012-R2-Standard-Edition-VM-2018.01.10-0", "2018-01-10T00:00:00Z"), image("Windows-Server-2012-R2-Standard-Edition-VM-2017.11.23-0", "2017-11-23T00:00:00Z")},
+		// Names that don't follow the form fall back to time_created, and
+		// are older than those that do.
+		{image("golden-b", "2018-01-02T00:00:00Z"), image("golden-a", "2018-01-01T00:00:00Z")},
+		{image("Oracle-Linux-7.4-2017.12.18-0", "2017-12-18T00:00:00Z"), image("golden", "2018-01-01T00:00:00Z")},
+	}
+	for _, pair := range newerThan {
+		assert.True(t, compareImages(pair[0], pair[1]) > 0, "%s should be newer than %s", pair[0].DisplayName, pair[1].DisplayName)
+		assert.True(t, compareImages(pair[1], pair[0]) < 0, "%s should be older than %s", pair[1].DisplayName, pair[0].DisplayName)
+	}
+	assert.Equal(t, 0, compareImages(image("Oracle-Linux-7.4-2017.12.18-0", "2017-12-18T00:00:00Z"), image("Oracle-Linux-7.4-2017.12.18-0", "2017-12-18T00:00:00Z")))
+}
+
+// imageListStandIn lists images, in no particular order, and records the
+// query of each request.
+type imageListStandIn struct {
+	sync.Mutex
+	images  []map[string]interface{}
+	queries []map[string][]string
+}
+
+func (i *imageListStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	i.Lock()
+	defer i.Unlock()
+
+	i.queries = append(i.queries, r.URL.Query())
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(i.images)
+}
+
+func TestImageLookupDatasource(t *testing.T) {
+	standIn := &imageListStandIn{images: []map[string]interface{}{
+		{"id": "ocid1.image.oc1..a", "displayName": "Oracle-Linux-7.4-2017.12.18-0", "lifecycleState": "AVAILABLE", "timeCreated": "2017-12-18T00:00:00.000Z"},
+		{"id": "ocid1.image.oc1..b", "displayName": "Oracle-Linux-7.4-2018.01.10-0", "lifecycleState": "AVAILABLE", "timeCreated": "2018-01-10T00:00:00.000Z"},
+		{"id": "ocid1.image.oc1..c", "displayName": "Oracle-Linux-7.4-2018.01.20-0", "lifecycleState": "PROVISIONING", "timeCreated": "2018-01-20T00:00:00.000Z"},
+		{"id": "ocid1.image.oc1..d", "displayName": "Oracle-Linux-7.4-Gen2-GPU-2018.01.10-0", "lifecycleState": "AVAILABLE", "timeCreated": "2018-01-10T00:00:00.000Z"},
+		{"id": "ocid1.image.oc1..e", "displayName": "Oracle-Linux-7.4-2017.11.15-0", "lifecycleState": "AVAILABLE", "timeCreated": "2017-11-15T00:00:00.000Z"},
+	}}
+	clients, closeServer, err := newStandInClient(standIn.ServeHTTP, func(d *schema.ResourceData) {})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := dataSourcesMap()["oci_core_image"]
+	read := func(c map[string]interface{}) (*terraform.InstanceState, error) {
+		c["compartment_id"] = "ocid1.compartment.oc1..standin"
+		raw, err := config.NewRawConfig(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		diff, err := r.Diff(nil, terraform.NewResourceConfig(raw))
+		if err != nil {
+			return nil, err
+		}
+		return r.ReadDataApply(diff, clients)
+	}
+
+	// The newest available image that matches is picked.
+	state, err := read(map[string]interface{}{
+		"operating_system":         "Oracle Linux",
+		"operating_system_version": "7.4",
+		"shape_compatible":         "VM.Standard1.1",
+		"name_pattern":             `^Oracle-Linux-7\.4-\d{4}\.`,
+		"most_recent":              true,
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "ocid1.image.oc1..b", state.ID)
+	assert.Equal(t, "Oracle-Linux-7.4-2018.01.10-0", state.Attributes["display_name"])
+	if assert.Len(t, standIn.queries, 1) {
+		assert.Equal(t, []string{"Oracle Linux"}, standIn.queries[0]["operatingSystem"])
+		assert.Equal(t, []string{"7.4"}, standIn.queries[0]["operatingSystemVersion"])
+		assert.Equal(t, []string{"VM.Standard1.1"}, standIn.queries[0]["shape"])
+	}
+
+	// A single match doesn't need most_recent.
+	state, err = read(map[string]interface{}{"name_pattern": "GPU"})
+	if assert.Nil(t, err) {
+		assert.Equal(t, "ocid1.image.oc1..d", state.ID)
+	}
+
+	// Without most_recent, several matches are an error, and so is none.
+	_, err = read(map[string]interface{}{"name_pattern": "^Oracle-Linux-7\\.4-2017"})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "2 images match the arguments")
+	}
+	_, err = read(map[string]interface{}{"name_pattern": "^Ubuntu"})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "No available image")
+	}
+
+	// The pattern must be a valid regular expression.
+	raw, err := config.NewRawConfig(map[string]interface{}{"compartment_id": "ocid1.compartment.oc1..standin", "name_pattern": "("})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, errs := r.Validate(terraform.NewResourceConfig(raw))
+	assert.Len(t, errs, 1)
+}
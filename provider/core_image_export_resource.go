@@ -0,0 +1,198 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/oracle/bmcs-go-sdk"
+
+	"github.com/oracle/terraform-provider-oci/crud"
+)
+
+// ImageExportResource exports an image to Object Storage. It is identified
+// by the work request of the export, and destroying it leaves the exported
+// object in place.
+func ImageExportResource() *schema.Resource {
+	return &schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: &crud.TwoHours,
+			Delete: &crud.FiveMinutes,
+		},
+		Create: createImageExport,
+		Read:   readImageExport,
+		Delete: deleteImageExport,
+		Schema: map[string]*schema.Schema{
+			"bucket_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"destination_type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					string(baremetal.ImageSourceTypeObjectStorageTuple),
+					string(baremetal.ImageSourceTypeObjectStorageURI),
+				}, false),
+			},
+			"destination_uri": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				ForceNew:  true,
+				Sensitive: true,
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"image_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"namespace_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"object_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"time_finished": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func createImageExport(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
+	sync := &ImageExportResourceCrud{}
+	sync.D = d
+	sync.Client = client.client
+	return crud.CreateResource(d, sync)
+}
+
+func readImageExport(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
+	sync := &ImageExportResourceCrud{}
+	sync.D = d
+	sync.Client = client.clientWithoutNotFoundRetries
+	return crud.ReadResource(sync)
+}
+
+func deleteImageExport(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
+	sync := &ImageExportResourceCrud{}
+	sync.D = d
+	sync.Client = client.clientWithoutNotFoundRetries
+	return crud.DeleteResource(d, sync)
+}
+
+type ImageExportResourceCrud struct {
+	crud.BaseCrud
+	WorkRequest *baremetal.CoreWorkRequest
+}
+
+func (s *ImageExportResourceCrud) ID() string {
+	return s.WorkRequest.ID
+}
+
+func (s *ImageExportResourceCrud) CreatedPending() []string {
+	return []string{
+		baremetal.WorkRequestAccepted,
+		baremetal.WorkRequestInProgress,
+		baremetal.WorkRequestCanceling,
+	}
+}
+
+func (s *ImageExportResourceCrud) CreatedTarget() []string {
+	return []string{
+		baremetal.WorkRequestSucceeded,
+		baremetal.WorkRequestFailed,
+	}
+}
+
+func (s *ImageExportResourceCrud) Create() (e error) {
+	imageID := s.D.Get("image_id").(string)
+	destination := &baremetal.ImageSourceDetails{
+		DestinationType: baremetal.ImageSourceType(s.D.Get("destination_type").(string)),
+	}
+	if destination.DestinationType == baremetal.ImageSourceTypeObjectStorageURI {
+		if destination.DestinationURI = s.D.Get("destination_uri").(string); destination.DestinationURI == "" {
+			return errors.New("destination_uri is required for objectStorageUri")
+		}
+	} else {
+		destination.BucketName = s.D.Get("bucket_name").(string)
+		destination.ObjectName = s.D.Get("object_name").(string)
+		if destination.BucketName == "" || destination.ObjectName == "" {
+			return errors.New("bucket_name and object_name are required for objectStorageTuple")
+		}
+		if destination.NamespaceName, e = resolveObjectStorageNamespace(s.Client, s.D.Get("namespace_name").(string)); e != nil {
+			return
+		}
+		s.D.Set("namespace_name", destination.NamespaceName)
+	}
+
+	image, e := s.Client.ExportImage(imageID, destination, nil)
+	if e != nil {
+		return
+	}
+	if image.WorkRequestID == "" {
+		return fmt.Errorf("Export of image %s did not return a work request", imageID)
+	}
+	s.WorkRequest = &baremetal.CoreWorkRequest{ID: image.WorkRequestID, State: baremetal.WorkRequestAccepted}
+	return
+}
+
+func (s *ImageExportResourceCrud) Get() (e error) {
+	res, e := s.Client.GetCoreWorkRequest(s.D.Id())
+	if e == nil {
+		s.WorkRequest = res
+		return
+	}
+	// Finished work requests are eventually purged, but the image stays
+	// exported.
+	if crud.IsNotFound(e) && s.D.Get("state").(string) == baremetal.WorkRequestSucceeded {
+		log.Printf("[DEBUG] Work request %q of the image export is gone, keeping it as %s", s.D.Id(), baremetal.WorkRequestSucceeded)
+		s.WorkRequest = &baremetal.CoreWorkRequest{ID: s.D.Id(), State: baremetal.WorkRequestSucceeded}
+		return nil
+	}
+	return
+}
+
+func (s *ImageExportResourceCrud) SetData() {
+	s.D.Set("state", s.WorkRequest.State)
+	if s.WorkRequest.TimeFinished != nil {
+		s.D.Set("time_finished", s.WorkRequest.TimeFinished.String())
+	}
+}
+
+// Delete only forgets the export. The exported object is left in Object
+// Storage.
+func (s *ImageExportResourceCrud) Delete() (e error) {
+	return
+}
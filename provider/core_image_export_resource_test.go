@@ -0,0 +1,174 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/oracle/bmcs-go-sdk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResourceCoreImageExportTestSuite struct {
+	suite.Suite
+	Providers    map[string]terraform.ResourceProvider
+	Config       string
+	ResourceName string
+}
+
+func (s *ResourceCoreImageExportTestSuite) SetupTest() {
+	s.Providers = testAccProviders
+	s.Config = testProviderConfig() + instanceConfig + `
+	data "oci_objectstorage_namespace" "t" {
+	}
+
+	resource "oci_objectstorage_bucket" "t" {
+		compartment_id = "${var.compartment_id}"
+		namespace = "${data.oci_objectstorage_namespace.t.namespace}"
+		name = "-tf-image-export"
+	}
+
+	resource "oci_core_image" "t" {
+		compartment_id = "${var.compartment_id}"
+		instance_id = "${oci_core_instance.t.id}"
+		timeouts {
+			create = "30m"
+		}
+	}`
+	s.ResourceName = "oci_core_image_export.t"
+}
+
+func (s *ResourceCoreImageExportTestSuite) TestAccResourceCoreImageExport_basic() {
+	resource.Test(s.T(), resource.TestCase{
+		Providers: s.Providers,
+		Steps: []resource.TestStep{
+			{
+				Config: s.Config + `
+				resource "oci_core_image_export" "t" {
+					image_id = "${oci_core_image.t.id}"
+					destination_type = "objectStorageTuple"
+					bucket_name = "${oci_objectstorage_bucket.t.name}"
+					object_name = "-tf-image"
+				}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(s.ResourceName, "id"),
+					resource.TestCheckResourceAttrSet(s.ResourceName, "namespace_name"),
+					resource.TestCheckResourceAttr(s.ResourceName, "state", baremetal.WorkRequestSucceeded),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceCoreImageExportTestSuite(t *testing.T) {
+	suite.Run(t, new(ResourceCoreImageExportTestSuite))
+}
+
+// imageExportStandIn serves the Object Storage namespace "standin" and the
+// work request of an image export, which is IN_PROGRESS until the next poll
+// and then ends up as finalState. Once purged, the work request is not found.
+type imageExportStandIn struct {
+	sync.Mutex
+	state      string
+	finalState string
+	purged     bool
+	exports    []map[string]interface{}
+}
+
+func (i *imageExportStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	i.Lock()
+	defer i.Unlock()
+
+	w.Header().Set("content-type", "application/json")
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/objectstorage/"):
+		w.Write([]byte(`"standin"`))
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/images/ocid1.image.oc1..standin/actions/export"):
+		export := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&export)
+		i.exports = append(i.exports, export)
+		i.state = baremetal.WorkRequestInProgress
+		w.Header().Set("opc-work-request-id", "ocid1.coreservicesworkrequest.oc1..standin")
+		w.Write([]byte(`{"id":"ocid1.image.oc1..standin","lifecycleState":"EXPORTING"}`))
+	case strings.HasSuffix(r.URL.Path, "/workRequests/ocid1.coreservicesworkrequest.oc1..standin") && !i.purged:
+		if i.state == baremetal.WorkRequestInProgress {
+			i.state = i.finalState
+		}
+		fmt.Fprintf(w, `{"id":"ocid1.coreservicesworkrequest.oc1..standin","operationType":"ExportImage",`+
+			`"status":"%s","timeAccepted":"2017-11-20T10:00:00.000Z","timeFinished":"2017-11-20T10:20:00.000Z"}`, i.state)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code":"NotAuthorizedOrNotFound","message":"not found"}`))
+	}
+}
+
+func TestImageExportResource(t *testing.T) {
+	standIn := &imageExportStandIn{finalState: baremetal.WorkRequestSucceeded}
+	clients, closeServer, err := newStandInClient(standIn.ServeHTTP, func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := resourcesMap()["oci_core_image_export"]
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"image_id":         "ocid1.image.oc1..standin",
+		"destination_type": "objectStorageTuple",
+		"bucket_name":      "images",
+		"object_name":      "golden",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(nil, terraform.NewResourceConfig(raw))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// Create waits for the work request to succeed, with the namespace
+	// defaulting to the tenancy's.
+	state, err := r.Apply(nil, diff, clients)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "ocid1.coreservicesworkrequest.oc1..standin", state.ID)
+	assert.Equal(t, baremetal.WorkRequestSucceeded, state.Attributes["state"])
+	assert.Equal(t, "standin", state.Attributes["namespace_name"])
+	assert.Equal(t, "2017-11-20 10:20:00 +0000 UTC", state.Attributes["time_finished"])
+	if assert.Len(t, standIn.exports, 1) {
+		assert.Equal(t, map[string]interface{}{
+			"destinationType": "objectStorageTuple",
+			"namespaceName":   "standin",
+			"bucketName":      "images",
+			"objectName":      "golden",
+		}, standIn.exports[0])
+	}
+
+	// The export outlives its work request.
+	standIn.purged = true
+	state, err = r.Refresh(state, clients)
+	assert.Nil(t, err)
+	if assert.NotNil(t, state) {
+		assert.Equal(t, "ocid1.coreservicesworkrequest.oc1..standin", state.ID)
+	}
+
+	// A failed export is an error, and is not recorded.
+	standIn.purged = false
+	standIn.finalState = baremetal.WorkRequestFailed
+	state, err = r.Apply(nil, diff, clients)
+	assert.NotNil(t, err)
+	assert.True(t, state == nil || state.ID == "")
+}
@@ -3,7 +3,10 @@
 package provider
 
 import (
+	"errors"
+
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/oracle/bmcs-go-sdk"
 
 	"github.com/oracle/terraform-provider-oci/crud"
@@ -14,11 +17,17 @@ func ImageResource() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
-		Timeouts: crud.DefaultTimeout,
-		Create:   createImage,
-		Read:     readImage,
-		Update:   updateImage,
-		Delete:   deleteImage,
+		// Imports from Object Storage can take much longer than the
+		// default timeout.
+		Timeouts: &schema.ResourceTimeout{
+			Create: &crud.TwoHours,
+			Update: &crud.FiveMinutes,
+			Delete: &crud.FiveMinutes,
+		},
+		Create: createImage,
+		Read:   readImage,
+		Update: updateImage,
+		Delete: deleteImage,
 		Schema: map[string]*schema.Schema{
 			"base_image_id": {
 				Type:     schema.TypeString,
@@ -38,14 +47,79 @@ func ImageResource() *schema.Resource {
 				Computed: true,
 				Optional: true,
 			},
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			// One of instance_id or image_source_details is required.
+			"image_source_details": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				ForceNew:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"instance_id"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"bucket_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"namespace_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+						},
+						"object_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"source_image_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								baremetal.SourceImageTypeQCOW2,
+								baremetal.SourceImageTypeVMDK,
+							}, false),
+						},
+						"source_type": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								string(baremetal.ImageSourceTypeObjectStorageTuple),
+								string(baremetal.ImageSourceTypeObjectStorageURI),
+							}, false),
+						},
+						"source_uri": {
+							Type:      schema.TypeString,
+							Optional:  true,
+							ForceNew:  true,
+							Sensitive: true,
+						},
+					},
+				},
+			},
 			"instance_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"image_source_details"},
+			},
+			"launch_mode": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					string(baremetal.ImageLaunchModeNative),
+					string(baremetal.ImageLaunchModeEmulated),
+					string(baremetal.ImageLaunchModeCustom),
+				}, false),
 			},
 			"state": {
 				Type:     schema.TypeString,
@@ -68,7 +142,10 @@ func ImageResource() *schema.Resource {
 }
 
 func createImage(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &ImageResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -76,7 +153,10 @@ func createImage(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readImage(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &ImageResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -84,7 +164,10 @@ func readImage(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func updateImage(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &ImageResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -92,7 +175,10 @@ func updateImage(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteImage(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &ImageResourceCrud{}
 	sync.D = d
 	sync.Client = client.clientWithoutNotFoundRetries
@@ -109,7 +195,10 @@ func (s *ImageResourceCrud) ID() string {
 }
 
 func (s *ImageResourceCrud) CreatedPending() []string {
-	return []string{baremetal.ResourceProvisioning}
+	return []string{
+		baremetal.ResourceProvisioning,
+		baremetal.ResourceImporting,
+	}
 }
 
 func (s *ImageResourceCrud) CreatedTarget() []string {
@@ -132,17 +221,56 @@ func (s *ImageResourceCrud) Create() (e error) {
 	compartmentID := s.D.Get("compartment_id").(string)
 	instanceID := s.D.Get("instance_id").(string)
 
-	opts := &baremetal.CreateOptions{}
+	opts := &baremetal.CreateImageOptions{}
 	displayName, ok := s.D.GetOk("display_name")
 	if ok {
 		opts.DisplayName = displayName.(string)
 	}
+	if launchMode, ok := s.D.GetOk("launch_mode"); ok {
+		opts.LaunchMode = baremetal.ImageLaunchMode(launchMode.(string))
+	}
+
+	if rawSourceDetails, ok := s.D.GetOk("image_source_details"); ok {
+		sourceDetails := rawSourceDetails.([]interface{})[0].(map[string]interface{})
+		if opts.ImageSourceDetails, e = s.imageSourceDetails(sourceDetails); e != nil {
+			return
+		}
+		// Record the namespace the image was imported from.
+		sourceDetails["namespace_name"] = opts.ImageSourceDetails.NamespaceName
+		s.D.Set("image_source_details", []interface{}{sourceDetails})
+	} else if instanceID == "" {
+		return errors.New("One of instance_id or image_source_details is required to create an image")
+	}
 
 	s.Res, e = s.Client.CreateImage(compartmentID, instanceID, opts)
 
 	return
 }
 
+// imageSourceDetails returns the Object Storage location in sourceDetails,
+// an image_source_details block, with its namespace resolved.
+func (s *ImageResourceCrud) imageSourceDetails(sourceDetails map[string]interface{}) (res *baremetal.ImageSourceDetails, e error) {
+	res = &baremetal.ImageSourceDetails{
+		SourceType:      baremetal.ImageSourceType(sourceDetails["source_type"].(string)),
+		SourceImageType: sourceDetails["source_image_type"].(string),
+	}
+	if res.SourceType == baremetal.ImageSourceTypeObjectStorageURI {
+		res.SourceURI = sourceDetails["source_uri"].(string)
+		if res.SourceURI == "" {
+			return nil, errors.New("image_source_details: source_uri is required for objectStorageUri")
+		}
+		return
+	}
+
+	res.BucketName = sourceDetails["bucket_name"].(string)
+	res.ObjectName = sourceDetails["object_name"].(string)
+	if res.BucketName == "" || res.ObjectName == "" {
+		return nil, errors.New("image_source_details: bucket_name and object_name are required for objectStorageTuple")
+	}
+	res.NamespaceName, e = resolveObjectStorageNamespace(s.Client, sourceDetails["namespace_name"].(string))
+	return
+}
+
 func (s *ImageResourceCrud) Get() (e error) {
 	res, e := s.Client.GetImage(s.D.Id())
 	if e == nil {
@@ -153,6 +281,7 @@ func (s *ImageResourceCrud) Get() (e error) {
 
 func (s *ImageResourceCrud) Update() (e error) {
 	opts := &baremetal.UpdateOptions{}
+	opts.IfMatch = s.IfMatch()
 	displayName, ok := s.D.GetOk("display_name")
 	if ok {
 		opts.DisplayName = displayName.(string)
@@ -168,6 +297,7 @@ func (s *ImageResourceCrud) SetData() {
 	s.D.Set("compartment_id", s.Res.CompartmentID)
 	s.D.Set("create_image_allowed", s.Res.CreateImageAllowed)
 	s.D.Set("display_name", s.Res.DisplayName)
+	s.D.Set("launch_mode", s.Res.LaunchMode)
 	s.D.Set("state", s.Res.State)
 	s.D.Set("operating_system", s.Res.OperatingSystem)
 	s.D.Set("operating_system_version", s.Res.OperatingSystemVersion)
@@ -175,5 +305,5 @@ func (s *ImageResourceCrud) SetData() {
 }
 
 func (s *ImageResourceCrud) Delete() (e error) {
-	return s.Client.DeleteImage(s.D.Id(), nil)
+	return s.Client.DeleteImage(s.D.Id(), s.IfMatchOptions())
 }
@@ -3,12 +3,20 @@
 package provider
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/oracle/bmcs-go-sdk"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -75,3 +83,110 @@ func (s *ResourceCoreImageTestSuite) TestAccResourceCoreImage_basic() {
 func TestResourceCoreImageTestSuite(t *testing.T) {
 	suite.Run(t, new(ResourceCoreImageTestSuite))
 }
+
+// imageStandIn serves the Object Storage namespace "standin" and a single
+// image, which is IMPORTING until the next poll after it is created.
+type imageStandIn struct {
+	sync.Mutex
+	state   string
+	creates []map[string]interface{}
+}
+
+func (i *imageStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	i.Lock()
+	defer i.Unlock()
+
+	w.Header().Set("content-type", "application/json")
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/objectstorage/"):
+		w.Write([]byte(`"standin"`))
+		return
+	case r.Method == http.MethodPost:
+		create := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&create)
+		i.creates = append(i.creates, create)
+		i.state = baremetal.ResourceImporting
+	case i.state == baremetal.ResourceImporting:
+		i.state = baremetal.ResourceAvailable
+	}
+	fmt.Fprintf(w, `{"id":"ocid1.image.oc1..standin","compartmentId":"ocid1.compartment.oc1..standin",`+
+		`"displayName":"golden","launchMode":"EMULATED","lifecycleState":"%s"}`, i.state)
+}
+
+func TestImageImport(t *testing.T) {
+	standIn := &imageStandIn{}
+	clients, closeServer, err := newStandInClient(standIn.ServeHTTP, func(d *schema.ResourceData) {})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := resourcesMap()["oci_core_image"]
+	create := func(c map[string]interface{}) (*terraform.InstanceState, error) {
+		c["compartment_id"] = "ocid1.compartment.oc1..standin"
+		raw, err := config.NewRawConfig(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		diff, err := r.Diff(nil, terraform.NewResourceConfig(raw))
+		if err != nil {
+			return nil, err
+		}
+		return r.Apply(nil, diff, clients)
+	}
+
+	// The namespace of a bucket and object defaults to the tenancy's.
+	state, err := create(map[string]interface{}{
+		"launch_mode": "EMULATED",
+		"image_source_details": []interface{}{map[string]interface{}{
+			"source_type":       "objectStorageTuple",
+			"bucket_name":       "images",
+			"object_name":       "golden.qcow2",
+			"source_image_type": "QCOW2",
+		}},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, baremetal.ResourceAvailable, state.Attributes["state"])
+	assert.Equal(t, "standin", state.Attributes["image_source_details.0.namespace_name"])
+	assert.Equal(t, "EMULATED", state.Attributes["launch_mode"])
+	if assert.Len(t, standIn.creates, 1) {
+		assert.Nil(t, standIn.creates[0]["instanceId"])
+		assert.Equal(t, "EMULATED", standIn.creates[0]["launchMode"])
+		assert.Equal(t, map[string]interface{}{
+			"sourceType":      "objectStorageTuple",
+			"namespaceName":   "standin",
+			"bucketName":      "images",
+			"objectName":      "golden.qcow2",
+			"sourceImageType": "QCOW2",
+		}, standIn.creates[0]["imageSourceDetails"])
+	}
+
+	// A URI, e.g. of a pre-authenticated request, is passed as is.
+	_, err = create(map[string]interface{}{
+		"image_source_details": []interface{}{map[string]interface{}{
+			"source_type": "objectStorageUri",
+			"source_uri":  "https://objectstorage.us-phoenix-1.oraclecloud.com/p/secret/n/standin/b/images/o/golden.vmdk",
+		}},
+	})
+	if assert.Nil(t, err) && assert.Len(t, standIn.creates, 2) {
+		assert.Equal(t, map[string]interface{}{
+			"sourceType": "objectStorageUri",
+			"sourceUri":  "https://objectstorage.us-phoenix-1.oraclecloud.com/p/secret/n/standin/b/images/o/golden.vmdk",
+		}, standIn.creates[1]["imageSourceDetails"])
+	}
+
+	// Something to create the image from is required.
+	_, err = create(map[string]interface{}{})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "One of instance_id or image_source_details is required")
+	}
+	_, err = create(map[string]interface{}{
+		"image_source_details": []interface{}{map[string]interface{}{"source_type": "objectStorageTuple"}},
+	})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "bucket_name and object_name are required")
+	}
+	assert.Len(t, standIn.creates, 2)
+}
@@ -52,7 +52,10 @@ func ImageDatasource() *schema.Resource {
 }
 
 func readImages(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &ImageDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
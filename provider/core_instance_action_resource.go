@@ -0,0 +1,159 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/oracle/bmcs-go-sdk"
+
+	"github.com/oracle/terraform-provider-oci/crud"
+)
+
+// InstanceActionResource performs an action on an instance when it is
+// created, and again whenever its triggers change.
+func InstanceActionResource() *schema.Resource {
+	return &schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: &crud.TwoHours,
+		},
+		Create: createInstanceAction,
+		Read:   readInstanceAction,
+		Delete: deleteInstanceAction,
+		Schema: map[string]*schema.Schema{
+			"action": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					string(baremetal.InstanceActionSoftReset),
+					string(baremetal.InstanceActionReset),
+				}, false),
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"instance_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"time_performed": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func createInstanceAction(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
+	sync := &InstanceActionResourceCrud{}
+	sync.D = d
+	sync.Client = client.client
+	return crud.CreateResource(d, sync)
+}
+
+func readInstanceAction(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
+	sync := &InstanceActionResourceCrud{}
+	sync.D = d
+	sync.Client = client.client
+	return crud.ReadResource(sync)
+}
+
+func deleteInstanceAction(d *schema.ResourceData, m interface{}) (e error) {
+	sync := &InstanceActionResourceCrud{}
+	sync.D = d
+	return crud.DeleteResource(d, sync)
+}
+
+// InstanceActionResourceCrud performs an action on the instance in
+// instance_id. Resource is that instance.
+type InstanceActionResourceCrud struct {
+	crud.BaseCrud
+	Resource *baremetal.Instance
+}
+
+func (s *InstanceActionResourceCrud) ID() string {
+	if s.D.Id() != "" {
+		return s.D.Id()
+	}
+	return resource.UniqueId()
+}
+
+// CreatedPending are the states an instance launches through, plus those it
+// passes through when it is reset.
+func (s *InstanceActionResourceCrud) CreatedPending() []string {
+	pending := (&InstanceResourceCrud{}).CreatedPending()
+	return append(pending, baremetal.ResourceStopping, baremetal.ResourceStopped)
+}
+
+func (s *InstanceActionResourceCrud) CreatedTarget() []string {
+	return (&InstanceResourceCrud{}).CreatedTarget()
+}
+
+func (s *InstanceActionResourceCrud) Create() (e error) {
+	instanceID := s.D.Get("instance_id").(string)
+	action := baremetal.InstanceActions(s.D.Get("action").(string))
+
+	if s.Resource, e = s.Client.InstanceAction(instanceID, action, nil); e != nil {
+		return
+	}
+	s.D.Set("time_performed", time.Now().UTC().Format(time.RFC3339))
+	s.D.Set("state", s.Resource.State)
+
+	// The instance is often still RUNNING when the action is accepted. Wait
+	// for the action to take it out of RUNNING first, so that the wait for
+	// CreatedTarget that follows is for the instance to come back.
+	if s.Resource.State != baremetal.ResourceRunning {
+		return
+	}
+	s.D.SetId(s.ID())
+	return crud.WaitForStateRefresh(s, s.D.Timeout(schema.TimeoutCreate),
+		[]string{baremetal.ResourceRunning},
+		[]string{baremetal.ResourceStopping, baremetal.ResourceStopped, baremetal.ResourceStarting})
+}
+
+// Get fetches the instance, so that the action is removed from the state
+// when the instance is gone.
+func (s *InstanceActionResourceCrud) Get() (e error) {
+	res, e := s.Client.GetInstance(s.D.Get("instance_id").(string))
+	if e != nil {
+		return
+	}
+	for _, target := range (&InstanceResourceCrud{}).DeletedTarget() {
+		if res.State == target {
+			return crud.NewMissingResourceError("Instance %s is %s", res.ID, res.State)
+		}
+	}
+	s.Resource = res
+	return
+}
+
+// SetData keeps the state that the instance reached after the action, rather
+// than the instance's current state.
+func (s *InstanceActionResourceCrud) SetData() {}
+
+func (s *InstanceActionResourceCrud) Delete() (e error) {
+	return
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/oracle/bmcs-go-sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstanceActionResource(t *testing.T) {
+	standIn := &instanceStandIn{state: baremetal.ResourceRunning}
+	clients, closeServer, err := newStandInClient(standIn.ServeHTTP, func(d *schema.ResourceData) {})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := resourcesMap()["oci_core_instance_action"]
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"instance_id": "ocid1.instance.oc1..standin",
+		"action":      "SOFTRESET",
+		"triggers":    map[string]interface{}{"kernel": "4.14.35"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(nil, terraform.NewResourceConfig(raw))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// The action waits for the instance to be RUNNING again.
+	state, err := r.Apply(nil, diff, clients)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.NotEmpty(t, state.ID)
+	assert.Equal(t, baremetal.ResourceRunning, state.Attributes["state"])
+	assert.NotEmpty(t, state.Attributes["time_performed"])
+	assert.Equal(t, []string{"SOFTRESET"}, standIn.actions)
+
+	// The recorded outcome isn't overwritten by later refreshes...
+	standIn.state = baremetal.ResourceStopped
+	state, err = r.Refresh(state, clients)
+	if assert.Nil(t, err) && assert.NotNil(t, state) {
+		assert.Equal(t, baremetal.ResourceRunning, state.Attributes["state"])
+	}
+
+	// ...but the action goes when the instance does.
+	standIn.state = baremetal.ResourceTerminated
+	state, err = r.Refresh(state, clients)
+	assert.Nil(t, err)
+	assert.Nil(t, state)
+}
+
+func TestInstanceActionResource_waitsForActionToStart(t *testing.T) {
+	// The instance is still RUNNING when the action is accepted, and at the
+	// first poll after it.
+	standIn := &instanceStandIn{state: baremetal.ResourceRunning, actionDelay: 2}
+	clients, closeServer, err := newStandInClient(standIn.ServeHTTP, func(d *schema.ResourceData) {})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := resourcesMap()["oci_core_instance_action"]
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"instance_id": "ocid1.instance.oc1..standin",
+		"action":      "RESET",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(nil, terraform.NewResourceConfig(raw))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	state, err := r.Apply(nil, diff, clients)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, baremetal.ResourceRunning, state.Attributes["state"])
+	// The wait saw the reset start, and the instance come back.
+	assert.Equal(t, 0, standIn.delayedPolls)
+	assert.Equal(t, baremetal.ResourceRunning, standIn.state)
+}
+
+func TestInstanceActionResource_invalidAction(t *testing.T) {
+	raw, _ := config.NewRawConfig(map[string]interface{}{
+		"instance_id": "ocid1.instance.oc1..standin",
+		"action":      "STOP",
+	})
+	_, errs := InstanceActionResource().Validate(terraform.NewResourceConfig(raw))
+	assert.Len(t, errs, 1)
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/oracle/bmcs-go-sdk"
+
+	"github.com/oracle/terraform-provider-oci/crud"
+)
+
+func InstanceConsoleConnectionResource() *schema.Resource {
+	return &schema.Resource{
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: crud.DefaultTimeout,
+		Create:   createInstanceConsoleConnection,
+		Read:     readInstanceConsoleConnection,
+		Delete:   deleteInstanceConsoleConnection,
+		Schema: map[string]*schema.Schema{
+			"compartment_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"connection_string": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"etag": crud.ETagSchema(),
+			"fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"instance_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"public_key": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"vnc_connection_string": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func createInstanceConsoleConnection(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
+	sync := &InstanceConsoleConnectionResourceCrud{}
+	sync.D = d
+	sync.Client = client.client
+	return crud.CreateResource(d, sync)
+}
+
+func readInstanceConsoleConnection(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
+	sync := &InstanceConsoleConnectionResourceCrud{}
+	sync.D = d
+	sync.Client = client.client
+	return crud.ReadResource(sync)
+}
+
+func deleteInstanceConsoleConnection(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
+	sync := &InstanceConsoleConnectionResourceCrud{}
+	sync.D = d
+	sync.Client = client.clientWithoutNotFoundRetries
+	return crud.DeleteResource(d, sync)
+}
+
+type InstanceConsoleConnectionResourceCrud struct {
+	crud.BaseCrud
+	Resource *baremetal.InstanceConsoleConnection
+}
+
+func (s *InstanceConsoleConnectionResourceCrud) ID() string {
+	return s.Resource.ID
+}
+
+func (s *InstanceConsoleConnectionResourceCrud) CreatedPending() []string {
+	return []string{baremetal.ResourceCreating}
+}
+
+func (s *InstanceConsoleConnectionResourceCrud) CreatedTarget() []string {
+	return []string{baremetal.ResourceActive}
+}
+
+func (s *InstanceConsoleConnectionResourceCrud) DeletedPending() []string {
+	return []string{baremetal.ResourceDeleting}
+}
+
+func (s *InstanceConsoleConnectionResourceCrud) DeletedTarget() []string {
+	return []string{baremetal.ResourceDeleted}
+}
+
+func (s *InstanceConsoleConnectionResourceCrud) Create() (e error) {
+	instanceID := s.D.Get("instance_id").(string)
+	publicKey := s.D.Get("public_key").(string)
+
+	s.Resource, e = s.Client.CreateInstanceConsoleConnection(instanceID, publicKey, nil)
+	return
+}
+
+func (s *InstanceConsoleConnectionResourceCrud) Get() (e error) {
+	res, e := s.Client.GetInstanceConsoleConnection(s.D.Id())
+	if e == nil {
+		s.Resource = res
+	}
+	return
+}
+
+func (s *InstanceConsoleConnectionResourceCrud) SetData() {
+	s.D.Set("compartment_id", s.Resource.CompartmentID)
+	s.D.Set("connection_string", s.Resource.ConnectionString)
+	s.D.Set("fingerprint", s.Resource.Fingerprint)
+	s.D.Set("instance_id", s.Resource.InstanceID)
+	s.D.Set("state", s.Resource.State)
+	s.D.Set("vnc_connection_string", s.Resource.VncConnectionString)
+}
+
+func (s *InstanceConsoleConnectionResourceCrud) Delete() (e error) {
+	return s.Client.DeleteInstanceConsoleConnection(s.D.Id(), s.IfMatchOptions())
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/oracle/bmcs-go-sdk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResourceCoreInstanceConsoleConnectionTestSuite struct {
+	suite.Suite
+	Providers    map[string]terraform.ResourceProvider
+	Config       string
+	ResourceName string
+}
+
+func (s *ResourceCoreInstanceConsoleConnectionTestSuite) SetupTest() {
+	s.Providers = testAccProviders
+	s.Config = testProviderConfig() + instanceConfig
+	s.ResourceName = "oci_core_instance_console_connection.t"
+}
+
+func (s *ResourceCoreInstanceConsoleConnectionTestSuite) TestAccResourceCoreInstanceConsoleConnection_basic() {
+	resource.Test(s.T(), resource.TestCase{
+		Providers: s.Providers,
+		Steps: []resource.TestStep{
+			{
+				Config: s.Config + `
+				resource "oci_core_instance_console_connection" "t" {
+					instance_id = "${oci_core_instance.t.id}"
+					public_key = "${var.ssh_public_key}"
+				}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(s.ResourceName, "id"),
+					resource.TestCheckResourceAttrSet(s.ResourceName, "compartment_id"),
+					resource.TestCheckResourceAttrSet(s.ResourceName, "connection_string"),
+					resource.TestCheckResourceAttrSet(s.ResourceName, "fingerprint"),
+					resource.TestCheckResourceAttr(s.ResourceName, "state", baremetal.ResourceActive),
+				),
+			},
+			{
+				ResourceName:      s.ResourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestResourceCoreInstanceConsoleConnectionTestSuite(t *testing.T) {
+	suite.Run(t, new(ResourceCoreInstanceConsoleConnectionTestSuite))
+}
+
+// consoleConnectionStandIn serves a single console connection, which is
+// CREATING or DELETING until the next poll.
+type consoleConnectionStandIn struct {
+	sync.Mutex
+	state     string
+	publicKey string
+}
+
+func (c *consoleConnectionStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.Lock()
+	defer c.Unlock()
+
+	w.Header().Set("content-type", "application/json")
+	switch {
+	case r.Method == http.MethodPost:
+		create := struct {
+			PublicKey string `json:"publicKey"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&create)
+		c.publicKey = create.PublicKey
+		c.state = baremetal.ResourceCreating
+	case r.Method == http.MethodDelete:
+		c.state = baremetal.ResourceDeleting
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case c.state == baremetal.ResourceCreating:
+		c.state = baremetal.ResourceActive
+	case c.state == baremetal.ResourceDeleting:
+		c.state = baremetal.ResourceDeleted
+	}
+	fmt.Fprintf(w, `{"id":"ocid1.instanceconsoleconnection.oc1..standin","compartmentId":"ocid1.compartment.oc1..standin",`+
+		`"instanceId":"ocid1.instance.oc1..standin","fingerprint":"d4:1f:00:ab",`+
+		`"connectionString":"ssh -o ProxyCommand='ssh -W %%h:%%p -p 443 ocid1.instanceconsoleconnection.oc1..standin@instance-console.us-phoenix-1.oraclecloud.com' ocid1.instance.oc1..standin",`+
+		`"vncConnectionString":"ssh -o ProxyCommand='ssh -W %%h:%%p -p 443 ocid1.instanceconsoleconnection.oc1..standin@instance-console.us-phoenix-1.oraclecloud.com' -N -L localhost:5900:ocid1.instance.oc1..standin:5900 ocid1.instance.oc1..standin",`+
+		`"lifecycleState":"%s"}`, c.state)
+}
+
+func TestInstanceConsoleConnectionResource(t *testing.T) {
+	standIn := &consoleConnectionStandIn{}
+	clients, closeServer, err := newStandInClient(standIn.ServeHTTP, func(d *schema.ResourceData) {})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := resourcesMap()["oci_core_instance_console_connection"]
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"instance_id": "ocid1.instance.oc1..standin",
+		"public_key":  "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQ on-call",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(nil, terraform.NewResourceConfig(raw))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// Create waits for the connection to be ACTIVE.
+	state, err := r.Apply(nil, diff, clients)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "ocid1.instanceconsoleconnection.oc1..standin", state.ID)
+	assert.Equal(t, "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQ on-call", standIn.publicKey)
+	assert.Equal(t, baremetal.ResourceActive, state.Attributes["state"])
+	assert.Equal(t, "d4:1f:00:ab", state.Attributes["fingerprint"])
+	assert.Contains(t, state.Attributes["connection_string"], "-W %h:%p -p 443")
+	assert.Contains(t, state.Attributes["vnc_connection_string"], "-L localhost:5900")
+
+	// Destroy waits for it to be DELETED.
+	state, err = r.Apply(state, &terraform.InstanceDiff{Destroy: true}, clients)
+	assert.Nil(t, err)
+	assert.Nil(t, state)
+	assert.Equal(t, baremetal.ResourceDeleted, standIn.state)
+}
@@ -3,27 +3,59 @@
 package provider
 
 import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform/helper/encryption"
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/oracle/bmcs-go-sdk"
+	"golang.org/x/crypto/ssh"
 
 	"github.com/oracle/terraform-provider-oci/crud"
 )
 
+// instanceCredentialsTimeout is how long to wait for the initial credentials
+// of a Windows instance, which are generated while it first boots.
+var instanceCredentialsTimeout = 20 * time.Minute
+
 func InstanceCredentialsDatasource() *schema.Resource {
 	return &schema.Resource{
 		Read: readInstanceCredentials,
 		Schema: map[string]*schema.Schema{
+			"encrypted_password": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"instance_id": {
 				Type:     schema.TypeString,
 				Required: true,
 			},
-			"username": {
+			"key_fingerprint": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
-			"password": {
+			"pgp_key": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"rsa_public_key"},
+			},
+			"rsa_public_key": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"pgp_key"},
+			},
+			"username": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
@@ -32,23 +64,59 @@ func InstanceCredentialsDatasource() *schema.Resource {
 }
 
 func readInstanceCredentials(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &InstanceCredentialsDatasourceCrud{}
 	sync.D = d
-	sync.Client = client.client
+	// Credentials that aren't generated yet are not found, which Get polls
+	// for itself.
+	sync.Client = client.clientWithoutNotFoundRetries
 	return crud.ReadResource(sync)
 }
 
+// InstanceCredentialsDatasourceCrud reads the initial credentials of a
+// Windows instance. The password is only kept encrypted, with pgp_key or
+// rsa_public_key, so that it never reaches the state in plain text.
 type InstanceCredentialsDatasourceCrud struct {
 	crud.BaseCrud
-	Res *baremetal.InstanceCredentials
+	Res               *baremetal.InstanceCredentials
+	EncryptedPassword string
+	KeyFingerprint    string
 }
 
 func (s *InstanceCredentialsDatasourceCrud) Get() (e error) {
+	pgpKey, rsaPublicKey := s.D.Get("pgp_key").(string), s.D.Get("rsa_public_key").(string)
+	if pgpKey == "" && rsaPublicKey == "" {
+		return errors.New("One of pgp_key or rsa_public_key is required to encrypt the instance's password")
+	}
+
 	instanceId := s.D.Get("instance_id").(string)
-	res, e := s.Client.GetWindowsInstanceInitialCredentials(instanceId)
-	if e == nil {
+	e = crud.Retry(s, instanceCredentialsTimeout, func() *resource.RetryError {
+		res, err := s.Client.GetWindowsInstanceInitialCredentials(instanceId)
+		if err != nil {
+			if crud.IsNotFound(err) || crud.IsConflict(err) || crud.IsRetryable(err) {
+				log.Printf("[DEBUG] Credentials of instance %q are not available yet: %v", instanceId, err)
+				// Retry returns the last error it retried once it times
+				// out. Wrapped, a 404 isn't mistaken for credentials
+				// that don't exist, which would void the data source.
+				return resource.RetryableError(fmt.Errorf("Timed out after %s waiting for the credentials of instance %s: %s",
+					instanceCredentialsTimeout, instanceId, err))
+			}
+			return resource.NonRetryableError(err)
+		}
 		s.Res = res
+		return nil
+	})
+	if e != nil {
+		return
+	}
+
+	if pgpKey != "" {
+		s.KeyFingerprint, s.EncryptedPassword, e = encryptWithPGPKey(pgpKey, s.Res.Password)
+	} else {
+		s.KeyFingerprint, s.EncryptedPassword, e = encryptWithRSAPublicKey(rsaPublicKey, s.Res.Password)
 	}
 	return
 }
@@ -57,7 +125,70 @@ func (s *InstanceCredentialsDatasourceCrud) SetData() {
 	if s.Res != nil {
 		s.D.SetId(time.Now().UTC().String())
 		s.D.Set("username", s.Res.Username)
-		s.D.Set("password", s.Res.Password)
+		s.D.Set("encrypted_password", s.EncryptedPassword)
+		s.D.Set("key_fingerprint", s.KeyFingerprint)
 	}
 	return
 }
+
+// encryptWithPGPKey encrypts value with pgpKey, a base64 encoded PGP public
+// key or "keybase:<username>".
+func encryptWithPGPKey(pgpKey, value string) (fingerprint, encrypted string, e error) {
+	if pgpKey, e = encryption.RetrieveGPGKey(pgpKey); e != nil {
+		return
+	}
+	return encryption.EncryptValue(pgpKey, value, "instance password")
+}
+
+// encryptWithRSAPublicKey encrypts value with RSA-OAEP and SHA-256, for
+// publicKey, a PEM encoded or OpenSSH RSA public key. The fingerprint is that
+// of the key in the form OCI shows API signing keys in.
+func encryptWithRSAPublicKey(publicKey, value string) (fingerprint, encrypted string, e error) {
+	rsaKey, e := parseRSAPublicKey(publicKey)
+	if e != nil {
+		return
+	}
+
+	der, e := x509.MarshalPKIXPublicKey(rsaKey)
+	if e != nil {
+		return
+	}
+	sum := md5.Sum(der)
+	hexBytes := make([]string, len(sum))
+	for i, b := range sum {
+		hexBytes[i] = fmt.Sprintf("%02x", b)
+	}
+
+	ciphertext, e := rsa.EncryptOAEP(sha256.New(), rand.Reader, rsaKey, []byte(value), nil)
+	if e != nil {
+		return "", "", fmt.Errorf("Error encrypting instance password: %s", e)
+	}
+	return strings.Join(hexBytes, ":"), base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+func parseRSAPublicKey(publicKey string) (*rsa.PublicKey, error) {
+	var parsed interface{}
+	if block, _ := pem.Decode([]byte(publicKey)); block != nil {
+		var err error
+		if block.Type == "RSA PUBLIC KEY" {
+			parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		} else {
+			parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing rsa_public_key: %s", err)
+		}
+	} else {
+		sshKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+		if err != nil {
+			return nil, errors.New("rsa_public_key must be a PEM encoded or OpenSSH public key")
+		}
+		parsed = sshKey.(ssh.CryptoPublicKey).CryptoPublicKey()
+	}
+
+	rsaKey, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("rsa_public_key must be an RSA key")
+	}
+	return rsaKey, nil
+}
@@ -3,12 +3,25 @@
 package provider
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/vault/helper/pgpkeys"
 	baremetal "github.com/oracle/bmcs-go-sdk"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -83,11 +96,14 @@ func (s *DatasourceCoreInstanceCredentialTestSuite) TestAccDatasourceCoreInstanc
 				Config: s.Config + `
 				data "oci_core_instance_credentials" "s" {
 					instance_id = "${oci_core_instance.t.id}"
+					pgp_key = "` + pgpkeys.TestPubKey1 + `"
 				}`,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttrSet(s.ResourceName, "instance_id"),
 					resource.TestCheckResourceAttrSet(s.ResourceName, "username"),
-					resource.TestCheckResourceAttrSet(s.ResourceName, "password"),
+					resource.TestCheckResourceAttrSet(s.ResourceName, "encrypted_password"),
+					resource.TestCheckResourceAttrSet(s.ResourceName, "key_fingerprint"),
+					resource.TestCheckNoResourceAttr(s.ResourceName, "password"),
 				),
 			},
 		},
@@ -95,6 +111,158 @@ func (s *DatasourceCoreInstanceCredentialTestSuite) TestAccDatasourceCoreInstanc
 	)
 }
 
+// credentialsStandIn serves the credentials of an instance that has just
+// launched, which are not found until the second request.
+func credentialsStandIn(requests *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if atomic.AddInt32(requests, 1) == 1 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":"NotAuthorizedOrNotFound","message":"not found"}`))
+			return
+		}
+		w.Write([]byte(`{"username":"opc","password":"s3cr3t-Pa55"}`))
+	}
+}
+
+func readCredentialsFromStandIn(t *testing.T, config map[string]interface{}) (d *schema.ResourceData, requests int32, err error) {
+	clients, closeServer, err := newStandInClient(credentialsStandIn(&requests), func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if err != nil {
+		return
+	}
+	defer closeServer()
+
+	r := InstanceCredentialsDatasource()
+	d = r.Data(nil)
+	for k, v := range config {
+		d.Set(k, v)
+	}
+	err = readInstanceCredentials(d, clients)
+	return
+}
+
+func TestInstanceCredentials_pgpKey(t *testing.T) {
+	d, requests, err := readCredentialsFromStandIn(t, map[string]interface{}{
+		"instance_id": "ocid1.instance.oc1..standin",
+		"pgp_key":     pgpkeys.TestPubKey1,
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	// The first request finds no credentials yet.
+	assert.Equal(t, int32(2), requests)
+	assert.Equal(t, "opc", d.Get("username"))
+	assert.NotEmpty(t, d.Get("key_fingerprint"))
+
+	plaintext, err := pgpkeys.DecryptBytes(d.Get("encrypted_password").(string), pgpkeys.TestPrivKey1)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "s3cr3t-Pa55", plaintext.String())
+	}
+}
+
+func TestInstanceCredentials_rsaPublicKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	d, _, err := readCredentialsFromStandIn(t, map[string]interface{}{
+		"instance_id":    "ocid1.instance.oc1..standin",
+		"rsa_public_key": string(publicKey),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Regexp(t, "^([0-9a-f]{2}:){15}[0-9a-f]{2}$", d.Get("key_fingerprint"))
+
+	ciphertext, err := base64.StdEncoding.DecodeString(d.Get("encrypted_password").(string))
+	if !assert.Nil(t, err) {
+		return
+	}
+	plaintext, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, key, ciphertext, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "s3cr3t-Pa55", string(plaintext))
+	}
+}
+
+func TestInstanceCredentials_timeout(t *testing.T) {
+	defer func(timeout time.Duration) { instanceCredentialsTimeout = timeout }(instanceCredentialsTimeout)
+	instanceCredentialsTimeout = time.Second
+
+	clients, closeServer, err := newStandInClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code":"NotAuthorizedOrNotFound","message":"not found"}`))
+	}, func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	// Credentials that never appear fail the read, rather than reading as
+	// empty.
+	d := InstanceCredentialsDatasource().Data(nil)
+	d.Set("instance_id", "ocid1.instance.oc1..standin")
+	d.Set("pgp_key", pgpkeys.TestPubKey1)
+	err = readInstanceCredentials(d, clients)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Timed out after 1s waiting for the credentials of instance ocid1.instance.oc1..standin")
+	}
+}
+
+func TestInstanceCredentials_interrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var requests int32
+	clients, closeServer, err := newStandInClient(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 2 {
+			cancel()
+		}
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code":"NotAuthorizedOrNotFound","message":"not found"}`))
+	}, func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+	clients.stopContext = ctx
+
+	// Interrupting Terraform stops the wait for the credentials.
+	d := InstanceCredentialsDatasource().Data(nil)
+	d.Set("instance_id", "ocid1.instance.oc1..standin")
+	d.Set("pgp_key", pgpkeys.TestPubKey1)
+	start := time.Now()
+	err = readInstanceCredentials(d, clients)
+	assert.True(t, time.Since(start) < 5*time.Second)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Interrupted")
+	}
+	time.Sleep(time.Second)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestInstanceCredentials_keyRequired(t *testing.T) {
+	_, requests, err := readCredentialsFromStandIn(t, map[string]interface{}{
+		"instance_id": "ocid1.instance.oc1..standin",
+	})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "pgp_key or rsa_public_key is required")
+	}
+	assert.Equal(t, int32(0), requests)
+}
+
 func TestDatasourceCoreInstanceCredentialTestSuite(t *testing.T) {
 	suite.Run(t, new(DatasourceCoreInstanceCredentialTestSuite))
 }
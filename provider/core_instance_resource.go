@@ -5,9 +5,12 @@ package provider
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/hashicorp/terraform/terraform"
 	"github.com/oracle/bmcs-go-sdk"
 
 	"github.com/oracle/terraform-provider-oci/crud"
@@ -24,10 +27,14 @@ func InstanceResource() *schema.Resource {
 			Update: &crud.TwoHours,
 			Delete: &crud.TwoHours,
 		},
-		Create: createInstance,
-		Read:   readInstance,
-		Update: updateInstance,
-		Delete: deleteInstance,
+		// Version 1 records the region name, rather than the API's short
+		// region code, in region. See migrateInstanceState.
+		SchemaVersion: 1,
+		MigrateState:  migrateInstanceState,
+		Create:        createInstance,
+		Read:          readInstance,
+		Update:        updateInstance,
+		Delete:        deleteInstance,
 		Schema: map[string]*schema.Schema{
 			"create_vnic_details": {
 				Type:     schema.TypeList,
@@ -49,11 +56,25 @@ func InstanceResource() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			"desired_state": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					baremetal.ResourceRunning,
+					baremetal.ResourceStopped,
+				}, false),
+			},
 			"display_name": {
 				Type:     schema.TypeString,
 				Optional: true,
 				Computed: true,
 			},
+			"boot_volume_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"etag": crud.ETagSchema(),
 			"hostname_label": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -64,37 +85,78 @@ func InstanceResource() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			// One of image or source_details is required. Each is computed
+			// from the other.
 			"image": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"source_details"},
 			},
 			"ipxe_script": {
 				Type:     schema.TypeString,
 				Optional: true,
 				ForceNew: true,
 			},
+			// Metadata is updated in place, except for the keys in
+			// instanceImmutableMetadataKeys. See customizeInstanceDiff.
 			"metadata": {
 				Type:     schema.TypeMap,
 				Optional: true,
 				Elem:     schema.TypeString,
-				ForceNew: true,
 			},
 			"extended_metadata": {
 				Type:     schema.TypeMap,
 				Optional: true,
-				ForceNew: true,
 				Elem:     schema.TypeString,
 			},
-			"region": {
-				Type:     schema.TypeString,
-				Computed: true,
+			// Only read when the instance is terminated, so it must be set
+			// before the change that replaces the instance.
+			"preserve_boot_volume": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			// Secondary VNICs are attached in order after launch, and
+			// matched to the attached VNICs by position.
+			"secondary_vnic": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     secondaryVnicSchema,
 			},
 			"shape": {
 				Type:     schema.TypeString,
 				Required: true,
 				ForceNew: true,
 			},
+			// Only the fields force a new instance, so that states from
+			// before source_details was read don't.
+			"source_details": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				Computed:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"image"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"source_id": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"source_type": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								string(baremetal.InstanceSourceTypeImage),
+								string(baremetal.InstanceSourceTypeBootVolume),
+							}, false),
+						},
+					},
+				},
+			},
 			"state": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -121,31 +183,94 @@ func InstanceResource() *schema.Resource {
 	}
 }
 
+// migrateInstanceState upgrades instance state from earlier versions. Before
+// version 1, region held the short region code the API reports, e.g. "phx",
+// which is not a region that clients can be built for. Codes that don't map
+// to a region name are dropped; instances were then always in the provider's
+// region, which Read records in their place.
+func migrateInstanceState(v int, is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+	if is.Empty() {
+		return is, nil
+	}
+
+	switch v {
+	case 0:
+		if code := is.Attributes["region"]; code != "" && validateRegion(code) != nil {
+			if name, ok := regionShortCodes[code]; ok {
+				log.Printf("[DEBUG] Migrating the region of instance %s from %q to %q", is.ID, code, name)
+				is.Attributes["region"] = name
+			} else {
+				log.Printf("[DEBUG] Dropping the unknown region code %q of instance %s, to be recorded again on refresh", code, is.ID)
+				delete(is.Attributes, "region")
+			}
+		}
+		return is, nil
+	}
+	return is, fmt.Errorf("Unexpected instance schema version: %d", v)
+}
+
 func createInstance(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &InstanceResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
-	return crud.CreateResource(d, sync)
+	sync.Client = client.client
+
+	// Instances launch RUNNING, with only their primary VNIC. Attach the
+	// secondary VNICs, then stop those that should be STOPPED, once they are
+	// up.
+	desiredState := d.Get("desired_state").(string)
+	secondaryVnics := d.Get("secondary_vnic").([]interface{})
+	if e = crud.CreateResource(d, sync); e != nil {
+		return
+	}
+	if len(secondaryVnics) == 0 && desiredState != baremetal.ResourceStopped {
+		return
+	}
+	if e = sync.syncSecondaryVnics(nil, secondaryVnics, schema.TimeoutCreate); e != nil {
+		return
+	}
+	if desiredState == baremetal.ResourceStopped {
+		d.Set("desired_state", desiredState)
+		if e = sync.changePowerState(); e != nil {
+			return
+		}
+	}
+	return crud.ReadResource(sync)
 }
 
 func readInstance(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &InstanceResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.ReadResource(sync)
 }
 
 func updateInstance(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &InstanceResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.UpdateResource(d, sync)
 }
 
 func deleteInstance(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &InstanceResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).clientWithoutNotFoundRetries
+	sync.Client = client.clientWithoutNotFoundRetries
 	return crud.DeleteResource(d, sync)
 }
 
@@ -181,6 +306,26 @@ func (s *InstanceResourceCrud) DeletedTarget() []string {
 	return []string{baremetal.ResourceTerminated}
 }
 
+// instanceImmutableMetadataKeys are the metadata keys that the API does not
+// allow to change after launch.
+var instanceImmutableMetadataKeys = []string{"ssh_authorized_keys", "user_data"}
+
+// customizeInstanceDiff forces a new instance when a metadata key that can't
+// be updated in place is added, changed or removed. The plan marks those keys
+// as forcing the new resource.
+func customizeInstanceDiff(s *terraform.InstanceState, c *terraform.ResourceConfig, diff *terraform.InstanceDiff, meta interface{}) error {
+	if s == nil || s.ID == "" {
+		return nil
+	}
+	for _, key := range instanceImmutableMetadataKeys {
+		if attr, ok := diff.Attributes["metadata."+key]; ok && (attr.Old != attr.New || attr.NewRemoved) {
+			log.Printf("[DEBUG] Instance %s must be replaced to change metadata.%s", s.ID, key)
+			attr.RequiresNew = true
+		}
+	}
+	return nil
+}
+
 func resourceInstanceMapToMetadata(rm map[string]interface{}) map[string]string {
 	result := map[string]string{}
 	for k, v := range rm {
@@ -235,6 +380,12 @@ func (s *InstanceResourceCrud) Create() (e error) {
 		opts.CreateVnicOptions = SetCreateVnicOptions(rawVnic.([]interface{}))
 	}
 
+	if rawSourceDetails, ok := s.D.GetOk("source_details"); ok {
+		opts.SourceDetails = mapToInstanceSourceDetails(rawSourceDetails.([]interface{})[0].(map[string]interface{}))
+	} else if image == "" {
+		e = errors.New("One of image or source_details is required to launch an instance")
+	}
+
 	if e == nil {
 		s.Resource, e = s.Client.LaunchInstance(
 			availabilityDomain,
@@ -248,6 +399,50 @@ func (s *InstanceResourceCrud) Create() (e error) {
 	return
 }
 
+func mapToInstanceSourceDetails(rm map[string]interface{}) *baremetal.InstanceSourceDetails {
+	sourceDetails := &baremetal.InstanceSourceDetails{
+		SourceType: baremetal.InstanceSourceType(rm["source_type"].(string)),
+	}
+	if sourceDetails.SourceType == baremetal.InstanceSourceTypeBootVolume {
+		sourceDetails.BootVolumeID = rm["source_id"].(string)
+	} else {
+		sourceDetails.ImageID = rm["source_id"].(string)
+	}
+	return sourceDetails
+}
+
+func instanceSourceDetailsToMap(sourceDetails *baremetal.InstanceSourceDetails) map[string]interface{} {
+	sourceID := sourceDetails.ImageID
+	if sourceDetails.SourceType == baremetal.InstanceSourceTypeBootVolume {
+		sourceID = sourceDetails.BootVolumeID
+	}
+	return map[string]interface{}{
+		"source_id":   sourceID,
+		"source_type": string(sourceDetails.SourceType),
+	}
+}
+
+// getBootVolumeID returns the OCID of the boot volume attached to the
+// instance, or "" if there is none.
+func (s *InstanceResourceCrud) getBootVolumeID() (bootVolumeID string, e error) {
+	opts := &baremetal.ListBootVolumeAttachmentsOptions{}
+	opts.InstanceID = s.Resource.ID
+	for {
+		var result *baremetal.ListBootVolumeAttachments
+		if result, e = s.Client.ListBootVolumeAttachments(s.Resource.AvailabilityDomain, s.Resource.CompartmentID, opts); e != nil {
+			return
+		}
+		for _, attachment := range result.BootVolumeAttachments {
+			if attachment.State == baremetal.ResourceAttaching || attachment.State == baremetal.ResourceAttached {
+				return attachment.BootVolumeID, nil
+			}
+		}
+		if hasNextPage := options.SetNextPageOption(result.NextPage, &opts.ListOptions.PageListOptions); !hasNextPage {
+			return
+		}
+	}
+}
+
 /*
  * Return the primary VNIC for this instance.
  *
@@ -301,10 +496,18 @@ func (s *InstanceResourceCrud) Get() (e error) {
 }
 
 func (s *InstanceResourceCrud) Update() (e error) {
-	opts := &baremetal.UpdateOptions{}
+	opts := &baremetal.UpdateInstanceOptions{}
+	opts.IfMatch = s.IfMatch()
 	if displayName, ok := s.D.GetOk("display_name"); ok {
 		opts.DisplayName = displayName.(string)
 	}
+	// The maps are replaced whole, so send them only when they change.
+	if s.D.HasChange("metadata") {
+		opts.Metadata = resourceInstanceMapToMetadata(s.D.Get("metadata").(map[string]interface{}))
+	}
+	if s.D.HasChange("extended_metadata") {
+		opts.ExtendedMetadata = mapToExtendedMetadata(s.D.Get("extended_metadata").(map[string]interface{}))
+	}
 
 	s.Resource, e = s.Client.UpdateInstance(s.D.Id(), opts)
 	if e != nil {
@@ -312,11 +515,29 @@ func (s *InstanceResourceCrud) Update() (e error) {
 	}
 
 	// HasChange returns true for any changes within create_vnic_details.
-	if !s.D.HasChange("create_vnic_details") {
+	if s.D.HasChange("create_vnic_details") {
+		if e = s.updatePrimaryVnic(); e != nil {
+			return
+		}
+	} else {
 		log.Printf("[DEBUG] No changes to primary VNIC. Instance ID: %q", s.Resource.ID)
-		return
 	}
 
+	if s.D.HasChange("secondary_vnic") {
+		oldVnics, newVnics := s.D.GetChange("secondary_vnic")
+		if e = s.syncSecondaryVnics(oldVnics.([]interface{}), newVnics.([]interface{}), schema.TimeoutUpdate); e != nil {
+			return
+		}
+	}
+
+	if s.D.HasChange("desired_state") {
+		e = s.changePowerState()
+	}
+
+	return
+}
+
+func (s *InstanceResourceCrud) updatePrimaryVnic() (e error) {
 	log.Printf("[DEBUG] Updating instance's primary VNIC. Instance ID: %q", s.Resource.ID)
 	vnic, e := s.getPrimaryVnic()
 	if e != nil {
@@ -331,6 +552,313 @@ func (s *InstanceResourceCrud) Update() (e error) {
 	return
 }
 
+// changePowerState starts or stops the instance to match desired_state, and
+// waits until it gets there.
+func (s *InstanceResourceCrud) changePowerState() (e error) {
+	desiredState := s.D.Get("desired_state").(string)
+
+	var action baremetal.InstanceActions
+	var pending []string
+	switch desiredState {
+	case baremetal.ResourceRunning:
+		action = baremetal.InstanceActionStart
+		pending = []string{baremetal.ResourceStopped, baremetal.ResourceStopping, baremetal.ResourceStarting}
+	case baremetal.ResourceStopped:
+		action = baremetal.InstanceActionStop
+		pending = []string{baremetal.ResourceRunning, baremetal.ResourceStarting, baremetal.ResourceStopping}
+	default:
+		return
+	}
+
+	if s.Resource.State == desiredState {
+		return
+	}
+
+	log.Printf("[DEBUG] Instance %q is %s, requesting %s", s.Resource.ID, s.Resource.State, action)
+	opts := &baremetal.HeaderOptions{}
+	opts.IfMatch = s.Resource.ETag
+	if s.Resource, e = s.Client.InstanceAction(s.D.Id(), action, opts); e != nil {
+		return
+	}
+
+	return crud.WaitForStateRefresh(s, s.D.Timeout(schema.TimeoutUpdate), pending, []string{desiredState})
+}
+
+// syncSecondaryVnics detaches, attaches and updates the instance's secondary
+// VNICs to go from oldVnics to newVnics, matching them by position. The VNICs
+// that are attached when it returns, even with an error, are recorded in
+// secondary_vnic, which is marked partial so that a failed update keeps it.
+func (s *InstanceResourceCrud) syncSecondaryVnics(oldVnics, newVnics []interface{}, timeout string) (e error) {
+	current := make([]interface{}, len(oldVnics))
+	copy(current, oldVnics)
+	for len(current) < len(newVnics) {
+		current = append(current, nil)
+	}
+	defer func() {
+		attached := []interface{}{}
+		for _, vnic := range current {
+			if vnic != nil {
+				attached = append(attached, vnic)
+			}
+		}
+		s.D.Set("secondary_vnic", attached)
+		s.D.SetPartial("secondary_vnic")
+	}()
+
+	// Detach the VNICs that were removed or must be replaced first, which
+	// frees their addresses for the VNICs that replace them.
+	for i, rawOldVnic := range oldVnics {
+		oldVnic := rawOldVnic.(map[string]interface{})
+		if i < len(newVnics) && !secondaryVnicMustBeReplaced(oldVnic, newVnics[i].(map[string]interface{})) {
+			continue
+		}
+		if e = s.detachSecondaryVnic(oldVnic, timeout); e != nil {
+			return
+		}
+		current[i] = nil
+	}
+
+	for i, rawNewVnic := range newVnics {
+		newVnic := rawNewVnic.(map[string]interface{})
+		var vnic map[string]interface{}
+		if current[i] == nil {
+			vnic, e = s.attachSecondaryVnic(newVnic, timeout)
+		} else {
+			vnic, e = s.updateSecondaryVnic(current[i].(map[string]interface{}), newVnic)
+		}
+		if vnic != nil {
+			current[i] = vnic
+		}
+		if e != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// secondaryVnicMustBeReplaced tells whether going from oldVnic to newVnic
+// changes something that the API cannot update on an attached VNIC.
+func secondaryVnicMustBeReplaced(oldVnic, newVnic map[string]interface{}) bool {
+	if oldVnic["vnic_attachment_id"] == "" {
+		return true
+	}
+	if newVnic["private_ip"] != "" && newVnic["private_ip"] != oldVnic["private_ip"] {
+		return true
+	}
+	return newVnic["subnet_id"] != oldVnic["subnet_id"] || newVnic["assign_public_ip"] != oldVnic["assign_public_ip"]
+}
+
+func (s *InstanceResourceCrud) attachSecondaryVnic(vnic map[string]interface{}, timeout string) (attached map[string]interface{}, e error) {
+	log.Printf("[DEBUG] Attaching secondary VNIC in subnet %q to instance %q", vnic["subnet_id"], s.Resource.ID)
+	attachment, e := s.Client.AttachVnic(s.Resource.ID, SetCreateVnicOptions([]interface{}{vnic}), nil)
+	if e != nil {
+		return
+	}
+
+	sync := &VnicAttachmentResourceCrud{}
+	if attachment, e = s.waitForVnicAttachment(sync, attachment.ID, timeout, sync.CreatedPending(), sync.CreatedTarget()); e != nil {
+		return
+	}
+	if attachment.State != baremetal.ResourceAttached {
+		return nil, fmt.Errorf("VNIC attachment %s is %s, expected %s", attachment.ID, attachment.State, baremetal.ResourceAttached)
+	}
+
+	attached = copySecondaryVnic(vnic)
+	attached["vnic_attachment_id"] = attachment.ID
+	attached["vnic_id"] = attachment.VnicID
+	attached["private_ips"] = []interface{}{}
+	// The VNIC is recorded as attached even if its private IPs fail, so that
+	// the next apply only retries those.
+	_, e = s.updateSecondaryVnic(attached, vnic)
+	attached["private_ips"] = vnic["private_ips"]
+	return
+}
+
+func (s *InstanceResourceCrud) updateSecondaryVnic(oldVnic, newVnic map[string]interface{}) (updated map[string]interface{}, e error) {
+	updated = copySecondaryVnic(oldVnic)
+	vnicID := oldVnic["vnic_id"].(string)
+
+	for _, key := range []string{"display_name", "hostname_label", "skip_source_dest_check"} {
+		if oldVnic[key] != newVnic[key] {
+			if _, e = s.Client.UpdateVnic(vnicID, SetUpdateVnicOptions([]interface{}{newVnic})); e != nil {
+				return
+			}
+			for _, key := range []string{"display_name", "hostname_label", "skip_source_dest_check"} {
+				updated[key] = newVnic[key]
+			}
+			break
+		}
+	}
+
+	oldIPs, newIPs := stringSet(oldVnic["private_ips"]), stringSet(newVnic["private_ips"])
+	var privateIPs []baremetal.PrivateIP
+	if privateIPs, e = s.listSecondaryPrivateIPs(vnicID); e != nil {
+		return
+	}
+	for _, privateIP := range privateIPs {
+		if oldIPs[privateIP.IPAddress] && !newIPs[privateIP.IPAddress] {
+			log.Printf("[DEBUG] Deleting private IP %q from VNIC %q", privateIP.IPAddress, vnicID)
+			if e = s.Client.DeletePrivateIP(privateIP.ID, nil); e != nil && !crud.IsNotFound(e) {
+				return
+			}
+			e = nil
+		}
+	}
+	for _, rawIP := range newVnic["private_ips"].([]interface{}) {
+		if ip := rawIP.(string); !oldIPs[ip] {
+			log.Printf("[DEBUG] Adding private IP %q to VNIC %q", ip, vnicID)
+			if _, e = s.Client.CreatePrivateIP(vnicID, &baremetal.CreatePrivateIPOptions{IPAddress: ip}); e != nil {
+				return
+			}
+		}
+	}
+	updated["private_ips"] = newVnic["private_ips"]
+	return
+}
+
+// detachSecondaryVnic detaches vnic, and waits until it is DETACHED.
+func (s *InstanceResourceCrud) detachSecondaryVnic(vnic map[string]interface{}, timeout string) (e error) {
+	attachmentID := vnic["vnic_attachment_id"].(string)
+	if attachmentID == "" {
+		return
+	}
+
+	log.Printf("[DEBUG] Detaching VNIC attachment %q from instance %q", attachmentID, s.Resource.ID)
+	if e = s.Client.DetachVnic(attachmentID, nil); e != nil {
+		if crud.IsNotFound(e) {
+			return nil
+		}
+		return
+	}
+
+	sync := &VnicAttachmentResourceCrud{}
+	_, e = s.waitForVnicAttachment(sync, attachmentID, timeout, sync.DeletedPending(), sync.DeletedTarget())
+	return
+}
+
+// waitForVnicAttachment polls the VNIC attachment attachmentID through sync,
+// which keeps its state apart from the instance's.
+func (s *InstanceResourceCrud) waitForVnicAttachment(sync *VnicAttachmentResourceCrud, attachmentID, timeout string, pending, target []string) (attachment *baremetal.VnicAttachment, e error) {
+	sync.D = VnicAttachmentResource().Data(nil)
+	sync.D.SetId(attachmentID)
+	sync.Client = s.Client
+	if e = crud.WaitForStateRefresh(sync, s.D.Timeout(timeout), pending, target); e != nil {
+		return
+	}
+	if sync.Resource == nil {
+		// The attachment was gone by the time it was polled.
+		return &baremetal.VnicAttachment{ID: attachmentID, State: baremetal.ResourceDetached}, nil
+	}
+	return sync.Resource, nil
+}
+
+// listSecondaryPrivateIPs returns the private IPs of the VNIC vnicID, other
+// than its primary private IP.
+func (s *InstanceResourceCrud) listSecondaryPrivateIPs(vnicID string) (privateIPs []baremetal.PrivateIP, e error) {
+	opts := &baremetal.ListPrivateIPsOptions{VnicID: vnicID}
+	for {
+		var result *baremetal.ListPrivateIPs
+		if result, e = s.Client.ListPrivateIPs(opts); e != nil {
+			return
+		}
+		for _, privateIP := range result.PrivateIPs {
+			if !privateIP.IsPrimary {
+				privateIPs = append(privateIPs, privateIP)
+			}
+		}
+		if hasNextPage := options.SetNextPageOption(result.NextPage, &opts.ListOptions.PageListOptions); !hasNextPage {
+			return
+		}
+	}
+}
+
+// refreshSecondaryVnics reads the VNICs recorded in secondary_vnic back from
+// the API. VNICs that have been detached are dropped, so that the next apply
+// attaches them again. VNICs that were attached by other means, e.g. with
+// oci_core_vnic_attachment, are left out.
+func (s *InstanceResourceCrud) refreshSecondaryVnics() {
+	vnics := []interface{}{}
+	for _, rawVnic := range s.D.Get("secondary_vnic").([]interface{}) {
+		vnic := copySecondaryVnic(rawVnic.(map[string]interface{}))
+		attachmentID := vnic["vnic_attachment_id"].(string)
+		if attachmentID == "" {
+			continue
+		}
+
+		attachment, err := s.Client.GetVnicAttachment(attachmentID)
+		if err != nil {
+			if !crud.IsNotFound(err) {
+				log.Printf("[WARN] VNIC attachment could not be read during instance refresh: %q (VNIC attachment ID: %q)", err, attachmentID)
+				vnics = append(vnics, vnic)
+			}
+			continue
+		}
+		if attachment.State == baremetal.ResourceDetaching || attachment.State == baremetal.ResourceDetached {
+			continue
+		}
+		vnics = append(vnics, vnic)
+
+		vnicDetails, err := s.Client.GetVnic(attachment.VnicID)
+		if vnicDetails == nil {
+			log.Printf("[WARN] VNIC could not be read during instance refresh: %q (VNIC ID: %q)", err, attachment.VnicID)
+			continue
+		}
+		vnic["vnic_id"] = vnicDetails.ID
+		vnic["subnet_id"] = vnicDetails.SubnetID
+		vnic["assign_public_ip"] = len(vnicDetails.PublicIPAddress) > 0
+		vnic["display_name"] = vnicDetails.DisplayName
+		vnic["hostname_label"] = vnicDetails.HostnameLabel
+		vnic["private_ip"] = vnicDetails.PrivateIPAddress
+		vnic["public_ip"] = vnicDetails.PublicIPAddress
+		vnic["skip_source_dest_check"] = vnicDetails.SkipSourceDestCheck
+
+		privateIPs, err := s.listSecondaryPrivateIPs(vnicDetails.ID)
+		if err != nil {
+			log.Printf("[WARN] Private IPs could not be listed during instance refresh: %q (VNIC ID: %q)", err, vnicDetails.ID)
+			continue
+		}
+		// Keep the configured order of the addresses, followed by any that
+		// were added elsewhere.
+		found := map[string]bool{}
+		for _, privateIP := range privateIPs {
+			found[privateIP.IPAddress] = true
+		}
+		ips := []interface{}{}
+		for _, rawIP := range vnic["private_ips"].([]interface{}) {
+			if found[rawIP.(string)] {
+				ips = append(ips, rawIP)
+				delete(found, rawIP.(string))
+			}
+		}
+		for _, privateIP := range privateIPs {
+			if found[privateIP.IPAddress] {
+				ips = append(ips, privateIP.IPAddress)
+			}
+		}
+		vnic["private_ips"] = ips
+	}
+	s.D.Set("secondary_vnic", vnics)
+}
+
+func copySecondaryVnic(vnic map[string]interface{}) map[string]interface{} {
+	vnicCopy := make(map[string]interface{}, len(vnic))
+	for k, v := range vnic {
+		vnicCopy[k] = v
+	}
+	return vnicCopy
+}
+
+// stringSet returns the strings in rawList, a []interface{}, as a set.
+func stringSet(rawList interface{}) map[string]bool {
+	set := map[string]bool{}
+	list, _ := rawList.([]interface{})
+	for _, rawString := range list {
+		set[rawString.(string)] = true
+	}
+	return set
+}
+
 func (s *InstanceResourceCrud) SetData() {
 	s.D.Set("availability_domain", s.Resource.AvailabilityDomain)
 	s.D.Set("compartment_id", s.Resource.CompartmentID)
@@ -338,10 +866,30 @@ func (s *InstanceResourceCrud) SetData() {
 	s.D.Set("image", s.Resource.ImageID)
 	s.D.Set("ipxe_script", s.Resource.IpxeScript)
 	s.D.Set("metadata", s.Resource.Metadata)
-	s.D.Set("region", s.Resource.Region)
 	s.D.Set("shape", s.Resource.Shape)
 	s.D.Set("state", s.Resource.State)
 	s.D.Set("time_created", s.Resource.TimeCreated.String())
+	sourceDetails := s.Resource.SourceDetails
+	if sourceDetails == nil && s.Resource.ImageID != "" {
+		sourceDetails = &baremetal.InstanceSourceDetails{SourceType: baremetal.InstanceSourceTypeImage, ImageID: s.Resource.ImageID}
+	}
+	if sourceDetails != nil {
+		s.D.Set("source_details", []interface{}{instanceSourceDetailsToMap(sourceDetails)})
+	}
+
+	// Report an instance that was stopped or started elsewhere as drift from
+	// desired_state, but not one that is still on its way there.
+	switch s.Resource.State {
+	case baremetal.ResourceRunning, baremetal.ResourceStopped:
+		s.D.Set("desired_state", s.Resource.State)
+		s.refreshSecondaryVnics()
+
+		if bootVolumeID, err := s.getBootVolumeID(); err != nil {
+			log.Printf("[WARN] Boot volume attachments could not be listed during instance refresh: %q (Instance ID: %q)", err, s.Resource.ID)
+		} else {
+			s.D.Set("boot_volume_id", bootVolumeID)
+		}
+	}
 
 	if s.Resource.State != baremetal.ResourceRunning {
 		return
@@ -362,5 +910,8 @@ func (s *InstanceResourceCrud) SetData() {
 }
 
 func (s *InstanceResourceCrud) Delete() (e error) {
-	return s.Client.TerminateInstance(s.D.Id(), nil)
+	opts := &baremetal.TerminateInstanceOptions{}
+	opts.IfMatch = s.IfMatch()
+	opts.PreserveBootVolume = s.D.Get("preserve_boot_volume").(bool)
+	return s.Client.TerminateInstance(s.D.Id(), opts)
 }
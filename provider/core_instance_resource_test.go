@@ -3,14 +3,21 @@
 package provider
 
 import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/oracle/bmcs-go-sdk"
 
 	"fmt"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/oracle/terraform-provider-oci/crud"
@@ -323,6 +330,640 @@ func (s *ResourceCoreInstanceTestSuite) TestAccResourceCoreInstance_basic() {
 	})
 }
 
+func (s *ResourceCoreInstanceTestSuite) TestAccResourceCoreInstance_desiredState() {
+	config := s.Config + `
+	resource "oci_core_instance" "t" {
+		availability_domain = "${data.oci_identity_availability_domains.ADs.availability_domains.0.name}"
+		compartment_id = "${var.compartment_id}"
+		subnet_id = "${oci_core_subnet.t.id}"
+		image = "${var.InstanceImageOCID[var.region]}"
+		shape = "VM.Standard1.1"
+		desired_state = "%s"
+		timeouts {
+			create = "15m"
+		}
+	}`
+
+	resource.Test(s.T(), resource.TestCase{
+		Providers: s.Providers,
+		Steps: []resource.TestStep{
+			// verify an instance can be launched stopped
+			{
+				Config: fmt.Sprintf(config, baremetal.ResourceStopped),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(s.ResourceName, "desired_state", baremetal.ResourceStopped),
+					resource.TestCheckResourceAttr(s.ResourceName, "state", baremetal.ResourceStopped),
+				),
+			},
+			// verify start
+			{
+				Config: fmt.Sprintf(config, baremetal.ResourceRunning),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(s.ResourceName, "desired_state", baremetal.ResourceRunning),
+					resource.TestCheckResourceAttr(s.ResourceName, "state", baremetal.ResourceRunning),
+					resource.TestCheckResourceAttrSet(s.ResourceName, "private_ip"),
+				),
+			},
+		},
+	})
+}
+
+// instanceStandIn serves a single instance, which goes through STOPPING or
+// STARTING on the next poll after a STOP or START action, and through
+// PROVISIONING or TERMINATING after a launch or terminate. Secondary VNICs
+// attached to it go through ATTACHING or DETACHING the same way.
+type instanceStandIn struct {
+	sync.Mutex
+	state   string
+	actions []string
+	updates []map[string]interface{}
+	vnics   []*vnicStandIn
+
+	// Each launch records its request, and each terminate its
+	// preserveBootVolume parameter. Launching creates the instance's boot
+	// volume, or attaches the one in sourceDetails if it still exists.
+	launches           []map[string]interface{}
+	terminations       []string
+	bootVolumeState    string
+	bootVolumeAttached bool
+
+	// When actionDelay is set, an action leaves the instance's state alone
+	// until the actionDelay-th poll after it, as the service often does.
+	actionDelay   int
+	delayedPolls  int
+	delayedAction string
+
+	// failAttach makes the failAttach-th VNIC attachment fail.
+	failAttach int
+}
+
+// vnicStandIn is a secondary VNIC and its attachment, both identified by
+// their index in instanceStandIn.vnics.
+type vnicStandIn struct {
+	state    string
+	subnetID string
+	publicIP string
+	ips      []string
+}
+
+func (i *instanceStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	i.Lock()
+	defer i.Unlock()
+
+	w.Header().Set("content-type", "application/json")
+	switch {
+	case strings.Contains(r.URL.Path, "/vnicAttachments") || strings.Contains(r.URL.Path, "/vnics/") ||
+		strings.Contains(r.URL.Path, "/privateIps"):
+		i.serveVnics(w, r)
+		return
+	case strings.HasSuffix(r.URL.Path, "/bootVolumeAttachments"):
+		i.serveBootVolumeAttachments(w)
+		return
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/instances"):
+		launch := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&launch)
+		if source, ok := launch["sourceDetails"].(map[string]interface{}); ok && source["sourceType"] == "bootVolume" &&
+			(source["bootVolumeId"] != "ocid1.bootvolume.oc1..standin" || i.bootVolumeState != baremetal.ResourceAvailable) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":"InvalidParameter","message":"boot volume is not available"}`))
+			return
+		}
+		i.launches = append(i.launches, launch)
+		i.state = baremetal.ResourceProvisioning
+		i.bootVolumeState = baremetal.ResourceAvailable
+		i.bootVolumeAttached = true
+	case r.Method == http.MethodDelete:
+		i.terminations = append(i.terminations, r.URL.Query().Get("preserveBootVolume"))
+		i.state = baremetal.ResourceTerminating
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case r.Method == http.MethodPut:
+		update := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&update)
+		i.updates = append(i.updates, update)
+	case r.Method == http.MethodPost:
+		action := r.URL.Query().Get("action")
+		i.actions = append(i.actions, action)
+		if i.actionDelay > 0 {
+			i.delayedAction, i.delayedPolls = action, i.actionDelay
+		} else {
+			i.applyAction(action)
+		}
+	case i.delayedPolls > 0:
+		if i.delayedPolls--; i.delayedPolls == 0 {
+			i.applyAction(i.delayedAction)
+		}
+	case i.state == baremetal.ResourceStopping:
+		i.state = baremetal.ResourceStopped
+	case i.state == baremetal.ResourceStarting || i.state == baremetal.ResourceProvisioning:
+		i.state = baremetal.ResourceRunning
+	case i.state == baremetal.ResourceTerminating:
+		i.state = baremetal.ResourceTerminated
+		i.bootVolumeAttached = false
+		if i.terminations[len(i.terminations)-1] != "true" {
+			i.bootVolumeState = baremetal.ResourceTerminated
+		}
+	}
+
+	shape, sourceDetails := "VM.Standard1.1", []byte("null")
+	if len(i.launches) > 0 {
+		launch := i.launches[len(i.launches)-1]
+		shape = launch["shape"].(string)
+		source, ok := launch["sourceDetails"]
+		if !ok {
+			source = map[string]interface{}{"sourceType": "image", "imageId": launch["imageId"]}
+		}
+		sourceDetails, _ = json.Marshal(source)
+	}
+	fmt.Fprintf(w, `{"id":"ocid1.instance.oc1..standin","availabilityDomain":"ad-1",`+
+		`"compartmentId":"ocid1.compartment.oc1..standin","imageId":"ocid1.image.oc1..standin",`+
+		`"shape":"%s","sourceDetails":%s,"lifecycleState":"%s"}`, shape, sourceDetails, i.state)
+}
+
+func (i *instanceStandIn) applyAction(action string) {
+	if action == string(baremetal.InstanceActionStop) {
+		i.state = baremetal.ResourceStopping
+	} else {
+		i.state = baremetal.ResourceStarting
+	}
+}
+
+func (i *instanceStandIn) serveBootVolumeAttachments(w http.ResponseWriter) {
+	attachments := []map[string]interface{}{}
+	if i.bootVolumeAttached {
+		attachments = append(attachments, map[string]interface{}{
+			"id":             "ocid1.instance.oc1..standin",
+			"bootVolumeId":   "ocid1.bootvolume.oc1..standin",
+			"instanceId":     "ocid1.instance.oc1..standin",
+			"lifecycleState": baremetal.ResourceAttached,
+		})
+	}
+	json.NewEncoder(w).Encode(attachments)
+}
+
+func (i *instanceStandIn) serveVnics(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	id := path[len(path)-1]
+	var vnic *vnicStandIn
+	var index int
+	if _, err := fmt.Sscanf(id[strings.LastIndex(id, ".")+1:], "%d", &index); err == nil && index < len(i.vnics) {
+		vnic = i.vnics[index]
+	}
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/vnicAttachments") && r.Method == http.MethodGet:
+		// Only the secondary VNICs are served, so the primary VNIC is never
+		// found.
+		w.Write([]byte(`[]`))
+		return
+	case strings.HasSuffix(r.URL.Path, "/vnicAttachments") && r.Method == http.MethodPost:
+		attach := struct {
+			CreateVnicDetails baremetal.CreateVnicOptions `json:"createVnicDetails"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&attach)
+		if len(i.vnics)+1 == i.failAttach {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":"InvalidParameter","message":"subnet has no free addresses"}`))
+			return
+		}
+		vnic = &vnicStandIn{state: baremetal.ResourceAttaching, subnetID: attach.CreateVnicDetails.SubnetID}
+		index = len(i.vnics)
+		if assign := attach.CreateVnicDetails.AssignPublicIp; assign == nil || *assign {
+			vnic.publicIP = fmt.Sprintf("129.146.0.%d", index)
+		}
+		i.vnics = append(i.vnics, vnic)
+	case strings.Contains(r.URL.Path, "/privateIps"):
+		i.servePrivateIPs(w, r)
+		return
+	case vnic == nil:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code":"NotAuthorizedOrNotFound","message":"not found"}`))
+		return
+	case strings.Contains(r.URL.Path, "/vnics/"):
+		fmt.Fprintf(w, `{"id":"ocid1.vnic.oc1..%d","subnetId":"%s","privateIp":"10.0.%d.2","publicIp":"%s","lifecycleState":"AVAILABLE"}`,
+			index, vnic.subnetID, index, vnic.publicIP)
+		return
+	case r.Method == http.MethodDelete:
+		vnic.state = baremetal.ResourceDetaching
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case vnic.state == baremetal.ResourceAttaching:
+		vnic.state = baremetal.ResourceAttached
+	case vnic.state == baremetal.ResourceDetaching:
+		vnic.state = baremetal.ResourceDetached
+	}
+	fmt.Fprintf(w, `{"id":"ocid1.vnicattachment.oc1..%d","instanceId":"ocid1.instance.oc1..standin",`+
+		`"subnetId":"%s","vnicId":"ocid1.vnic.oc1..%d","lifecycleState":"%s"}`, index, vnic.subnetID, index, vnic.state)
+}
+
+// servePrivateIPs serves the secondary private IPs of the VNICs. A private
+// IP's ID is its VNIC's index and its address.
+func (i *instanceStandIn) servePrivateIPs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var index int
+		fmt.Sscanf(r.URL.Query().Get("vnicId"), "ocid1.vnic.oc1..%d", &index)
+		privateIPs := []map[string]interface{}{}
+		for _, ip := range i.vnics[index].ips {
+			privateIPs = append(privateIPs, map[string]interface{}{"id": fmt.Sprintf("%d/%s", index, ip), "ipAddress": ip})
+		}
+		json.NewEncoder(w).Encode(privateIPs)
+	case http.MethodPost:
+		create := struct {
+			VnicID    string `json:"vnicId"`
+			IPAddress string `json:"ipAddress"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&create)
+		var index int
+		fmt.Sscanf(create.VnicID, "ocid1.vnic.oc1..%d", &index)
+		i.vnics[index].ips = append(i.vnics[index].ips, create.IPAddress)
+		fmt.Fprintf(w, `{"id":"%d/%s","ipAddress":"%s"}`, index, create.IPAddress, create.IPAddress)
+	case http.MethodDelete:
+		var index int
+		var ip string
+		fmt.Sscanf(strings.TrimPrefix(r.URL.Path[strings.Index(r.URL.Path, "/privateIps/"):], "/privateIps/"), "%d/%s", &index, &ip)
+		ips := []string{}
+		for _, existing := range i.vnics[index].ips {
+			if existing != ip {
+				ips = append(ips, existing)
+			}
+		}
+		i.vnics[index].ips = ips
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestInstanceDesiredState(t *testing.T) {
+	standIn := &instanceStandIn{state: baremetal.ResourceRunning}
+	clients, closeServer, err := newStandInClient(standIn.ServeHTTP, func(d *schema.ResourceData) {})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := resourcesMap()["oci_core_instance"]
+	state := &terraform.InstanceState{ID: "ocid1.instance.oc1..standin"}
+	apply := func(desiredState string) {
+		raw, err := config.NewRawConfig(map[string]interface{}{
+			"availability_domain": "ad-1",
+			"compartment_id":      "ocid1.compartment.oc1..standin",
+			"image":               "ocid1.image.oc1..standin",
+			"shape":               "VM.Standard1.1",
+			"desired_state":       desiredState,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		diff, err := r.Diff(state, terraform.NewResourceConfig(raw))
+		if !assert.Nil(t, err) || !assert.NotNil(t, diff) {
+			return
+		}
+		assert.False(t, diff.RequiresNew())
+		state, err = r.Apply(state, diff, clients)
+		assert.Nil(t, err)
+	}
+
+	// Stopping it in the console shows up as drift from desired_state.
+	state, err = r.Refresh(state, clients)
+	assert.Nil(t, err)
+	assert.Equal(t, baremetal.ResourceRunning, state.Attributes["desired_state"])
+	standIn.state = baremetal.ResourceStopped
+	state, err = r.Refresh(state, clients)
+	assert.Nil(t, err)
+	assert.Equal(t, baremetal.ResourceStopped, state.Attributes["desired_state"])
+
+	apply(baremetal.ResourceRunning)
+	assert.Equal(t, baremetal.ResourceRunning, state.Attributes["state"])
+	assert.Equal(t, baremetal.ResourceRunning, state.Attributes["desired_state"])
+
+	apply(baremetal.ResourceStopped)
+	assert.Equal(t, baremetal.ResourceStopped, state.Attributes["state"])
+	assert.Equal(t, baremetal.ResourceStopped, state.Attributes["desired_state"])
+
+	assert.Equal(t, []string{"START", "STOP"}, standIn.actions)
+}
+
+func TestInstanceMetadataUpdate(t *testing.T) {
+	standIn := &instanceStandIn{state: baremetal.ResourceRunning}
+	clients, closeServer, err := newStandInClient(standIn.ServeHTTP, func(d *schema.ResourceData) {})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := resourcesMap()["oci_core_instance"]
+	state := &terraform.InstanceState{
+		ID: "ocid1.instance.oc1..standin",
+		Attributes: map[string]string{
+			"availability_domain":   "ad-1",
+			"compartment_id":        "ocid1.compartment.oc1..standin",
+			"image":                 "ocid1.image.oc1..standin",
+			"shape":                 "VM.Standard1.1",
+			"metadata.%":            "2",
+			"metadata.user_data":    "Y2xvdWQtaW5pdA==",
+			"metadata.build_number": "41",
+		},
+	}
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"availability_domain": "ad-1",
+		"compartment_id":      "ocid1.compartment.oc1..standin",
+		"image":               "ocid1.image.oc1..standin",
+		"shape":               "VM.Standard1.1",
+		"desired_state":       baremetal.ResourceRunning,
+		"metadata":            map[string]interface{}{"user_data": "Y2xvdWQtaW5pdA==", "build_number": "42"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(state, terraform.NewResourceConfig(raw))
+	if !assert.Nil(t, err) || !assert.False(t, diff.RequiresNew()) {
+		return
+	}
+	_, err = r.Apply(state, diff, clients)
+	assert.Nil(t, err)
+
+	// The whole metadata map is sent, and extended_metadata is left alone.
+	if assert.Len(t, standIn.updates, 1) {
+		assert.Equal(t, map[string]interface{}{"user_data": "Y2xvdWQtaW5pdA==", "build_number": "42"}, standIn.updates[0]["metadata"])
+		assert.Nil(t, standIn.updates[0]["extendedMetadata"])
+	}
+}
+
+func TestInstanceSecondaryVnics(t *testing.T) {
+	standIn := &instanceStandIn{state: baremetal.ResourceRunning}
+	clients, closeServer, err := newStandInClient(standIn.ServeHTTP, func(d *schema.ResourceData) {})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := resourcesMap()["oci_core_instance"]
+	state := &terraform.InstanceState{
+		ID: "ocid1.instance.oc1..standin",
+		Attributes: map[string]string{
+			"availability_domain": "ad-1",
+			"compartment_id":      "ocid1.compartment.oc1..standin",
+			"image":               "ocid1.image.oc1..standin",
+			"shape":               "VM.Standard1.1",
+		},
+	}
+	apply := func(secondaryVnics ...map[string]interface{}) {
+		raw, err := config.NewRawConfig(map[string]interface{}{
+			"availability_domain": "ad-1",
+			"compartment_id":      "ocid1.compartment.oc1..standin",
+			"image":               "ocid1.image.oc1..standin",
+			"shape":               "VM.Standard1.1",
+			"desired_state":       baremetal.ResourceRunning,
+			"secondary_vnic":      secondaryVnics,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		diff, err := r.Diff(state, terraform.NewResourceConfig(raw))
+		if !assert.Nil(t, err) || !assert.False(t, diff.RequiresNew()) {
+			return
+		}
+		state, err = r.Apply(state, diff, clients)
+		assert.Nil(t, err)
+
+		// What was applied is what is read back. The stand-in has no
+		// primary VNIC, so only secondary_vnic is compared.
+		state, err = r.Refresh(state, clients)
+		assert.Nil(t, err)
+		diff, err = r.Diff(state, terraform.NewResourceConfig(raw))
+		assert.Nil(t, err)
+		for k := range diff.Attributes {
+			assert.False(t, strings.HasPrefix(k, "secondary_vnic"), "unexpected diff of %s", k)
+		}
+	}
+
+	// The VNICs are attached in order, and each gets its private IPs.
+	apply(
+		map[string]interface{}{"subnet_id": "ocid1.subnet.oc1..a", "private_ips": []interface{}{"10.0.0.10", "10.0.0.11"}},
+		map[string]interface{}{"subnet_id": "ocid1.subnet.oc1..b"},
+	)
+	if assert.Len(t, standIn.vnics, 2) {
+		assert.Equal(t, "ocid1.subnet.oc1..a", standIn.vnics[0].subnetID)
+		assert.Equal(t, []string{"10.0.0.10", "10.0.0.11"}, standIn.vnics[0].ips)
+		assert.Equal(t, "ocid1.subnet.oc1..b", standIn.vnics[1].subnetID)
+	}
+	assert.Equal(t, "2", state.Attributes["secondary_vnic.#"])
+	assert.Equal(t, "ocid1.vnicattachment.oc1..0", state.Attributes["secondary_vnic.0.vnic_attachment_id"])
+	assert.Equal(t, "ocid1.vnic.oc1..0", state.Attributes["secondary_vnic.0.vnic_id"])
+	assert.Equal(t, "10.0.0.2", state.Attributes["secondary_vnic.0.private_ip"])
+	assert.Equal(t, "129.146.0.0", state.Attributes["secondary_vnic.0.public_ip"])
+	assert.Equal(t, "10.0.0.11", state.Attributes["secondary_vnic.0.private_ips.1"])
+	assert.Equal(t, "ocid1.vnic.oc1..1", state.Attributes["secondary_vnic.1.vnic_id"])
+
+	// Removing a VNIC detaches it, and changing private_ips adds and deletes
+	// only the addresses that changed.
+	apply(map[string]interface{}{"subnet_id": "ocid1.subnet.oc1..a", "private_ips": []interface{}{"10.0.0.11", "10.0.0.12"}})
+	assert.Equal(t, baremetal.ResourceDetached, standIn.vnics[1].state)
+	assert.Equal(t, []string{"10.0.0.11", "10.0.0.12"}, standIn.vnics[0].ips)
+	assert.Equal(t, "1", state.Attributes["secondary_vnic.#"])
+	assert.Equal(t, "ocid1.vnic.oc1..0", state.Attributes["secondary_vnic.0.vnic_id"])
+
+	// Moving the VNIC to another subnet replaces the VNIC, not the instance.
+	apply(map[string]interface{}{"subnet_id": "ocid1.subnet.oc1..c"})
+	if assert.Len(t, standIn.vnics, 3) {
+		assert.Equal(t, baremetal.ResourceDetached, standIn.vnics[0].state)
+		assert.Equal(t, baremetal.ResourceAttached, standIn.vnics[2].state)
+	}
+	assert.Equal(t, "ocid1.vnic.oc1..2", state.Attributes["secondary_vnic.0.vnic_id"])
+	assert.Equal(t, "0", state.Attributes["secondary_vnic.0.private_ips.#"])
+}
+
+func TestInstanceSecondaryVnics_failedAttach(t *testing.T) {
+	standIn := &instanceStandIn{state: baremetal.ResourceRunning, failAttach: 2}
+	clients, closeServer, err := newStandInClient(standIn.ServeHTTP, func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := resourcesMap()["oci_core_instance"]
+	state := &terraform.InstanceState{
+		ID: "ocid1.instance.oc1..standin",
+		Attributes: map[string]string{
+			"availability_domain": "ad-1",
+			"compartment_id":      "ocid1.compartment.oc1..standin",
+			"image":               "ocid1.image.oc1..standin",
+			"shape":               "VM.Standard1.1",
+		},
+	}
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"availability_domain": "ad-1",
+		"compartment_id":      "ocid1.compartment.oc1..standin",
+		"image":               "ocid1.image.oc1..standin",
+		"shape":               "VM.Standard1.1",
+		"desired_state":       baremetal.ResourceRunning,
+		"secondary_vnic": []map[string]interface{}{
+			{"subnet_id": "ocid1.subnet.oc1..a"},
+			{"subnet_id": "ocid1.subnet.oc1..b"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The second attachment fails, but the first VNIC stays in the state.
+	diff, err := r.Diff(state, terraform.NewResourceConfig(raw))
+	if !assert.Nil(t, err) {
+		return
+	}
+	state, err = r.Apply(state, diff, clients)
+	assert.NotNil(t, err)
+	if !assert.NotNil(t, state) {
+		return
+	}
+	assert.Equal(t, "1", state.Attributes["secondary_vnic.#"])
+	assert.Equal(t, "ocid1.vnic.oc1..0", state.Attributes["secondary_vnic.0.vnic_id"])
+
+	// The next apply attaches only the VNIC that is missing.
+	standIn.failAttach = 0
+	diff, err = r.Diff(state, terraform.NewResourceConfig(raw))
+	if !assert.Nil(t, err) {
+		return
+	}
+	state, err = r.Apply(state, diff, clients)
+	assert.Nil(t, err)
+	assert.Len(t, standIn.vnics, 2)
+	assert.Equal(t, "2", state.Attributes["secondary_vnic.#"])
+	assert.Equal(t, "ocid1.vnic.oc1..0", state.Attributes["secondary_vnic.0.vnic_id"])
+	assert.Equal(t, "ocid1.vnic.oc1..1", state.Attributes["secondary_vnic.1.vnic_id"])
+}
+
+func TestInstanceBootVolumeReattachment(t *testing.T) {
+	standIn := &instanceStandIn{}
+	clients, closeServer, err := newStandInClient(standIn.ServeHTTP, func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := resourcesMap()["oci_core_instance"]
+	var state *terraform.InstanceState
+	apply := func(c map[string]interface{}) (*terraform.InstanceDiff, error) {
+		c["availability_domain"] = "ad-1"
+		c["compartment_id"] = "ocid1.compartment.oc1..standin"
+		raw, err := config.NewRawConfig(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		diff, err := r.Diff(state, terraform.NewResourceConfig(raw))
+		if err != nil {
+			return nil, err
+		}
+		newState, err := r.Apply(state, diff, clients)
+		if newState != nil {
+			state = newState
+		}
+		return diff, err
+	}
+	fromBootVolume := []interface{}{map[string]interface{}{
+		"source_type": "bootVolume",
+		"source_id":   "ocid1.bootvolume.oc1..standin",
+	}}
+
+	// An instance launched from an image exports its boot volume.
+	_, err = apply(map[string]interface{}{
+		"image":                "ocid1.image.oc1..standin",
+		"shape":                "VM.Standard1.1",
+		"preserve_boot_volume": true,
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "ocid1.bootvolume.oc1..standin", state.Attributes["boot_volume_id"])
+	assert.Equal(t, "image", state.Attributes["source_details.0.source_type"])
+	assert.Equal(t, "ocid1.image.oc1..standin", state.Attributes["source_details.0.source_id"])
+
+	// Changing the shape replaces the instance with one that boots from the
+	// preserved boot volume, without an image.
+	diff, err := apply(map[string]interface{}{
+		"shape":                "VM.Standard1.2",
+		"source_details":       fromBootVolume,
+		"preserve_boot_volume": true,
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, diff.RequiresNew())
+	assert.Equal(t, []string{"true"}, standIn.terminations)
+	if assert.Len(t, standIn.launches, 2) {
+		assert.Nil(t, standIn.launches[1]["imageId"])
+		assert.Equal(t, map[string]interface{}{"sourceType": "bootVolume", "bootVolumeId": "ocid1.bootvolume.oc1..standin"},
+			standIn.launches[1]["sourceDetails"])
+	}
+	assert.Equal(t, "VM.Standard1.2", state.Attributes["shape"])
+	assert.Equal(t, "bootVolume", state.Attributes["source_details.0.source_type"])
+	assert.Equal(t, "ocid1.bootvolume.oc1..standin", state.Attributes["boot_volume_id"])
+
+	// preserve_boot_volume changes in place. Without it, the boot volume is
+	// terminated with the instance.
+	diff, err = apply(map[string]interface{}{
+		"shape":          "VM.Standard1.2",
+		"source_details": fromBootVolume,
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.False(t, diff.RequiresNew())
+	assert.Equal(t, "false", state.Attributes["preserve_boot_volume"])
+	state, err = r.Apply(state, &terraform.InstanceDiff{Destroy: true}, clients)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"true", ""}, standIn.terminations)
+	assert.Equal(t, baremetal.ResourceTerminated, standIn.bootVolumeState)
+	_, err = apply(map[string]interface{}{
+		"shape":          "VM.Standard1.2",
+		"source_details": fromBootVolume,
+	})
+	assert.NotNil(t, err)
+
+	// Neither an image nor source_details is an error.
+	_, err = apply(map[string]interface{}{"shape": "VM.Standard1.1"})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "One of image or source_details is required")
+	}
+}
+
+func TestInstanceStateMigration(t *testing.T) {
+	r := InstanceResource()
+
+	// Short region codes recorded before version 1 become region names.
+	is, err := r.MigrateState(0, &terraform.InstanceState{
+		ID:         "ocid1.instance.oc1..standin",
+		Attributes: map[string]string{"region": "phx"},
+	}, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "us-phoenix-1", is.Attributes["region"])
+	}
+
+	is, err = r.MigrateState(0, &terraform.InstanceState{
+		ID:         "ocid1.instance.oc1..standin",
+		Attributes: map[string]string{"region": "us-ashburn-1"},
+	}, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "us-ashburn-1", is.Attributes["region"])
+	}
+
+	// Codes of other regions are dropped, for refresh to record the
+	// provider's region.
+	is, err = r.MigrateState(0, &terraform.InstanceState{
+		ID:         "ocid1.instance.oc1..standin",
+		Attributes: map[string]string{"region": "yyz", "shape": "VM.Standard1.1"},
+	}, nil)
+	if assert.Nil(t, err) {
+		_, ok := is.Attributes["region"]
+		assert.False(t, ok)
+		assert.Equal(t, "VM.Standard1.1", is.Attributes["shape"])
+	}
+}
+
 func TestIsStatefulResource(t *testing.T) {
 	var _ crud.StatefulResource = (*InstanceResourceCrud)(nil)
 }
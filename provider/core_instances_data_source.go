@@ -105,7 +105,10 @@ func resourceCoreInstance() *schema.Resource {
 }
 
 func readInstances(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &InstanceDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
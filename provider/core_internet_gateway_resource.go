@@ -25,6 +25,7 @@ func InternetGatewayResource() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			"etag": crud.ETagSchema(),
 			"vcn_id": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -61,34 +62,50 @@ func InternetGatewayResource() *schema.Resource {
 }
 
 func createInternetGateway(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &InternetGatewayResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 
 	return crud.CreateResource(d, sync)
 }
 
 func readInternetGateway(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &InternetGatewayResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 
 	return crud.ReadResource(sync)
 }
 
 func updateInternetGateway(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &InternetGatewayResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 
 	return crud.UpdateResource(d, sync)
 
 }
 
 func deleteInternetGateway(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &InternetGatewayResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).clientWithoutNotFoundRetries
+	sync.Client = client.clientWithoutNotFoundRetries
 	return crud.DeleteResource(d, sync)
 }
 
@@ -147,6 +164,7 @@ func (s *InternetGatewayResourceCrud) Get() (e error) {
 
 func (s *InternetGatewayResourceCrud) Update() (e error) {
 	opts := &baremetal.UpdateGatewayOptions{}
+	opts.IfMatch = s.IfMatch()
 
 	// todo: GetOk malfunction with this bool: 'ok' is always the value of the bool
 	// newer versions of terraform support GetOkExists which should resolve this problem
@@ -175,5 +193,5 @@ func (s *InternetGatewayResourceCrud) SetData() {
 }
 
 func (s *InternetGatewayResourceCrud) Delete() (e error) {
-	return s.Client.DeleteInternetGateway(s.D.Id(), nil)
+	return s.Client.DeleteInternetGateway(s.D.Id(), s.IfMatchOptions())
 }
@@ -44,7 +44,10 @@ func InternetGatewayDatasource() *schema.Resource {
 }
 
 func readInternetGateways(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &InternetGatewayDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
@@ -55,7 +55,10 @@ func IPSecConnectionConfigDatasource() *schema.Resource {
 }
 
 func readIPSecDeviceConfig(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &IPSecConnectionConfigDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
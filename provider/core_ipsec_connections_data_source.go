@@ -90,7 +90,10 @@ func datasourceIPSecConnections() *schema.Resource {
 }
 
 func readIPSecConnections(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &IPSecConnectionsDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
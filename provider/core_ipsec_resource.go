@@ -35,6 +35,7 @@ func IPSecConnectionResource() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			"etag": crud.ETagSchema(),
 			"static_routes": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -61,21 +62,32 @@ func IPSecConnectionResource() *schema.Resource {
 }
 
 func createIPSec(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &IPSecConnectionResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.CreateResource(d, sync)
 }
 
 func readIPSec(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &IPSecConnectionResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.ReadResource(sync)
 }
 
 func updateIPSec(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &IPSecConnectionResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -83,9 +95,13 @@ func updateIPSec(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteIPSec(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &IPSecConnectionResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).clientWithoutNotFoundRetries
+	sync.Client = client.clientWithoutNotFoundRetries
 	return crud.DeleteResource(d, sync)
 }
 
@@ -150,6 +166,7 @@ func (s *IPSecConnectionResourceCrud) Get() (e error) {
 
 func (s *IPSecConnectionResourceCrud) Update() (e error) {
 	opts := &baremetal.IfMatchDisplayNameOptions{}
+	opts.IfMatch = s.IfMatch()
 	displayName, ok := s.D.GetOk("display_name")
 	if ok {
 		opts.DisplayName = displayName.(string)
@@ -171,5 +188,5 @@ func (s *IPSecConnectionResourceCrud) SetData() {
 }
 
 func (s *IPSecConnectionResourceCrud) Delete() (e error) {
-	return s.Client.DeleteIPSecConnection(s.D.Id(), nil)
+	return s.Client.DeleteIPSecConnection(s.D.Id(), s.IfMatchOptions())
 }
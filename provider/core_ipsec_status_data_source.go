@@ -59,7 +59,10 @@ func IPSecConnectionStatusDatasource() *schema.Resource {
 }
 
 func readIPSecDeviceStatus(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &IPSecConnectionStatusDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
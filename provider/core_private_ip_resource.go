@@ -21,6 +21,7 @@ func PrivateIPResource() *schema.Resource {
 		Delete:   deletePrivateIP,
 		Schema: map[string]*schema.Schema{
 			//Required
+			"etag": crud.ETagSchema(),
 			"vnic_id": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -74,30 +75,46 @@ func PrivateIPResource() *schema.Resource {
 }
 
 func createPrivateIP(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &PrivateIPResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.CreateResource(d, sync)
 }
 
 func readPrivateIP(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &PrivateIPResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.ReadResource(sync)
 }
 
 func updatePrivateIP(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &PrivateIPResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.UpdateResource(d, sync)
 }
 
 func deletePrivateIP(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &PrivateIPResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).clientWithoutNotFoundRetries
+	sync.Client = client.clientWithoutNotFoundRetries
 	return crud.DeleteResource(d, sync)
 }
 
@@ -141,6 +158,7 @@ func (s *PrivateIPResourceCrud) Get() (e error) {
 
 func (s *PrivateIPResourceCrud) Update() (e error) {
 	opts := &baremetal.UpdatePrivateIPOptions{}
+	opts.IfMatch = s.IfMatch()
 	if displayName, ok := s.D.GetOk("display_name"); ok {
 		opts.DisplayName = displayName.(string)
 	}
@@ -168,5 +186,5 @@ func (s *PrivateIPResourceCrud) SetData() {
 }
 
 func (s *PrivateIPResourceCrud) Delete() (e error) {
-	return s.Client.DeletePrivateIP(s.D.Id(), nil)
+	return s.Client.DeletePrivateIP(s.D.Id(), s.IfMatchOptions())
 }
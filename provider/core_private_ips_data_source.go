@@ -41,7 +41,10 @@ func PrivateIPDatasource() *schema.Resource {
 }
 
 func readPrivateIPs(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &PrivateIPDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -7,6 +7,7 @@ import (
 	"github.com/oracle/bmcs-go-sdk"
 
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/oracle/terraform-provider-oci/crud"
@@ -28,6 +29,7 @@ func DefaultRouteTableResource() *schema.Resource {
 				Computed: true,
 				Optional: true,
 			},
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -43,8 +45,9 @@ func DefaultRouteTableResource() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"cidr_block": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateCIDRBlock,
 						},
 						"network_entity_id": {
 							Type:     schema.TypeString,
@@ -90,6 +93,7 @@ func RouteTableResource() *schema.Resource {
 				Computed: true,
 				Optional: true,
 			},
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -100,8 +104,9 @@ func RouteTableResource() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"cidr_block": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateCIDRBlock,
 						},
 						"network_entity_id": {
 							Type:     schema.TypeString,
@@ -132,15 +137,22 @@ func RouteTableResource() *schema.Resource {
 }
 
 func createRouteTable(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	crd := &RouteTableResourceCrud{}
 	crd.D = d
 	crd.Client = client.client
+	crd.TargetClient = client.clientWithoutNotFoundRetries
 	return crud.CreateResource(d, crd)
 }
 
 func readRouteTable(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	crd := &RouteTableResourceCrud{}
 	crd.D = d
 	crd.Client = client.client
@@ -148,15 +160,22 @@ func readRouteTable(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func updateRouteTable(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	crd := &RouteTableResourceCrud{}
 	crd.D = d
 	crd.Client = client.client
+	crd.TargetClient = client.clientWithoutNotFoundRetries
 	return crud.UpdateResource(d, crd)
 }
 
 func deleteRouteTable(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	crd := &RouteTableResourceCrud{}
 	crd.D = d
 	crd.Client = client.clientWithoutNotFoundRetries
@@ -166,6 +185,10 @@ func deleteRouteTable(d *schema.ResourceData, m interface{}) (e error) {
 type RouteTableResourceCrud struct {
 	crud.BaseCrud
 	Res *baremetal.RouteTable
+	// TargetClient reads the network entities that route rules point at.
+	// It doesn't retry when they're not found, as that is reported as an
+	// error in the rule.
+	TargetClient *baremetal.Client
 }
 
 func (s *RouteTableResourceCrud) ID() string {
@@ -207,7 +230,7 @@ func (s *RouteTableResourceCrud) Create() (e error) {
 	opts := &baremetal.CreateOptions{}
 	opts.DisplayName = s.D.Get("display_name").(string)
 
-	rr, e := s.buildRouteRules()
+	rr, e := s.buildRouteRules(true)
 
 	if e != nil {
 		return e
@@ -237,12 +260,15 @@ func (s *RouteTableResourceCrud) Get() (e error) {
 
 func (s *RouteTableResourceCrud) Update() (e error) {
 	opts := &baremetal.UpdateRouteTableOptions{}
+	opts.IfMatch = s.IfMatch()
 
 	if displayName, ok := s.D.GetOk("display_name"); ok {
 		opts.DisplayName = displayName.(string)
 	}
 
-	opts.RouteRules, e = s.buildRouteRules()
+	// Targets of rules that aren't changing were checked when they were
+	// added, and may have changed since for reasons this update isn't about.
+	opts.RouteRules, e = s.buildRouteRules(s.D.HasChange("route_rules"))
 
 	if e != nil {
 		return e
@@ -276,6 +302,7 @@ func (s *RouteTableResourceCrud) reset() (e error) {
 	opts := &baremetal.UpdateRouteTableOptions{
 		RouteRules: []baremetal.RouteRule{},
 	}
+	opts.IfMatch = s.IfMatch()
 
 	_, e = s.Client.UpdateRouteTable(s.D.Id(), opts)
 	return
@@ -290,14 +317,16 @@ func (s *RouteTableResourceCrud) Delete() (e error) {
 		return
 	}
 
-	return s.Client.DeleteRouteTable(s.D.Id(), nil)
+	return s.Client.DeleteRouteTable(s.D.Id(), s.IfMatchOptions())
 }
 
 func (s *RouteTableResourceCrud) ExtraWaitPostCreateDelete() time.Duration {
 	return time.Duration(15 * time.Second)
 }
 
-func (s *RouteTableResourceCrud) buildRouteRules() (routeRules []baremetal.RouteRule, e error) {
+// buildRouteRules reads the route rules of the configuration, checking their
+// targets with checkRouteTarget if checkTargets is set.
+func (s *RouteTableResourceCrud) buildRouteRules(checkTargets bool) (routeRules []baremetal.RouteRule, e error) {
 	routeRules = []baremetal.RouteRule{}
 	for _, val := range s.D.Get("route_rules").([]interface{}) {
 
@@ -310,7 +339,70 @@ func (s *RouteTableResourceCrud) buildRouteRules() (routeRules []baremetal.Route
 			CidrBlock:       data["cidr_block"].(string),
 			NetworkEntityID: data["network_entity_id"].(string),
 		}
+		if checkTargets {
+			if e = checkRouteTarget(s.TargetClient, routeRule.NetworkEntityID); e != nil {
+				return nil, fmt.Errorf("route_rules.%d: %s", len(routeRules), e)
+			}
+		}
 		routeRules = append(routeRules, routeRule)
 	}
 	return
 }
+
+// checkRouteTarget verifies that the network entity a route rule points at
+// exists and is AVAILABLE, as the service only rejects the rule with a 400
+// that doesn't say which rule or why. The type of the entity is read from
+// its OCID, and entities of other types are left for the service to check.
+//
+// A private IP only receives the traffic routed to it if its VNIC skips the
+// source/destination check. That is only warned about, as the VNIC can be
+// updated after the route table.
+func checkRouteTarget(client *baremetal.Client, id string) error {
+	switch ocidResourceType(id) {
+	case "internetgateway":
+		gw, err := client.GetInternetGateway(id)
+		if err != nil {
+			return routeTargetError("Internet gateway", id, err)
+		}
+		return checkRouteTargetState("Internet gateway", id, gw.State)
+	case "drg":
+		drg, err := client.GetDrg(id)
+		if err != nil {
+			return routeTargetError("DRG", id, err)
+		}
+		return checkRouteTargetState("DRG", id, drg.State)
+	case "privateip":
+		privateIP, err := client.GetPrivateIP(id)
+		if err != nil {
+			return routeTargetError("Private IP", id, err)
+		}
+		vnic, err := client.GetVnic(privateIP.VnicID)
+		if err != nil {
+			return fmt.Errorf("Private IP %s: %s", id, routeTargetError("VNIC", privateIP.VnicID, err))
+		}
+		if err = checkRouteTargetState("VNIC", vnic.ID, vnic.State); err != nil {
+			return fmt.Errorf("Private IP %s: %s", id, err)
+		}
+		if !vnic.SkipSourceDestCheck {
+			log.Printf("[WARN] Private IP %s (%s) is a route target, but its VNIC %s has the source/destination check enabled, so it drops the traffic routed to it. Set skip_source_dest_check on the VNIC.", id, privateIP.IPAddress, vnic.ID)
+		}
+		return nil
+	default:
+		log.Printf("[DEBUG] Not checking route target %q, of an unknown type", id)
+		return nil
+	}
+}
+
+func routeTargetError(kind, id string, err error) error {
+	if crud.IsNotFound(err) {
+		return fmt.Errorf("%s %s does not exist, or is not visible to the provider's user", kind, id)
+	}
+	return fmt.Errorf("%s %s could not be read: %s", kind, id, err)
+}
+
+func checkRouteTargetState(kind, id, state string) error {
+	if state != baremetal.ResourceAvailable {
+		return fmt.Errorf("%s %s is %s, not %s", kind, id, state, baremetal.ResourceAvailable)
+	}
+	return nil
+}
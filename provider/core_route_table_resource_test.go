@@ -3,12 +3,18 @@
 package provider
 
 import (
+	"net/http"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/oracle/bmcs-go-sdk"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -166,3 +172,149 @@ func (s *ResourceCoreRouteTableTestSuite) TestAccResourceCoreRouteTable_basic()
 func TestResourceCoreRouteTableTestSuite(t *testing.T) {
 	suite.Run(t, new(ResourceCoreRouteTableTestSuite))
 }
+
+func TestOCIDResourceType(t *testing.T) {
+	assert.Equal(t, "drg", ocidResourceType("ocid1.drg.oc1.phx.aaaaaaaa"))
+	assert.Equal(t, "internetgateway", ocidResourceType("ocid1.internetgateway.oc1..standin"))
+	assert.Equal(t, "", ocidResourceType("drg"))
+	assert.Equal(t, "", ocidResourceType("10.0.0.1"))
+}
+
+// routeTargetStandIn serves the network entities route rules can point at,
+// and counts the route tables created.
+func routeTargetStandIn(routeTablesCreated *int32) http.HandlerFunc {
+	entities := map[string]string{
+		"/internetGateways/ocid1.internetgateway.oc1..available": `{"id":"ocid1.internetgateway.oc1..available","lifecycleState":"AVAILABLE"}`,
+		"/drgs/ocid1.drg.oc1..provisioning":                      `{"id":"ocid1.drg.oc1..provisioning","lifecycleState":"PROVISIONING"}`,
+		"/privateIps/ocid1.privateip.oc1..standin":               `{"id":"ocid1.privateip.oc1..standin","ipAddress":"10.0.0.5","vnicId":"ocid1.vnic.oc1..standin"}`,
+		"/vnics/ocid1.vnic.oc1..standin":                         `{"id":"ocid1.vnic.oc1..standin","lifecycleState":"AVAILABLE","skipSourceDestCheck":false}`,
+		"/routeTables/ocid1.routetable.oc1..standin":             `{"id":"ocid1.routetable.oc1..standin","lifecycleState":"AVAILABLE","displayName":"after","routeRules":[{"cidrBlock":"192.168.0.0/16","networkEntityId":"ocid1.drg.oc1..deleted"}]}`,
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/routeTables") {
+			atomic.AddInt32(routeTablesCreated, 1)
+		}
+		for path, body := range entities {
+			if strings.HasSuffix(r.URL.Path, path) {
+				w.Write([]byte(body))
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code":"NotAuthorizedOrNotFound","message":"not found"}`))
+	}
+}
+
+func TestCheckRouteTarget(t *testing.T) {
+	var created int32
+	clients, closeServer, err := newStandInClient(routeTargetStandIn(&created), func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+	client := clients.clientWithoutNotFoundRetries
+
+	assert.Nil(t, checkRouteTarget(client, "ocid1.internetgateway.oc1..available"))
+	// The VNIC of a private IP is only warned about.
+	assert.Nil(t, checkRouteTarget(client, "ocid1.privateip.oc1..standin"))
+	// Other types are left for the service to check.
+	assert.Nil(t, checkRouteTarget(client, "ocid1.localpeeringgateway.oc1..standin"))
+
+	err = checkRouteTarget(client, "ocid1.internetgateway.oc1..deleted")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Internet gateway ocid1.internetgateway.oc1..deleted does not exist, or is not visible to the provider's user", err.Error())
+	}
+	err = checkRouteTarget(client, "ocid1.drg.oc1..provisioning")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "DRG ocid1.drg.oc1..provisioning is PROVISIONING, not AVAILABLE", err.Error())
+	}
+	err = checkRouteTarget(client, "ocid1.privateip.oc1..deleted")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Private IP ocid1.privateip.oc1..deleted does not exist")
+	}
+}
+
+func TestRouteTableResource_missingTarget(t *testing.T) {
+	var created int32
+	clients, closeServer, err := newStandInClient(routeTargetStandIn(&created), func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := resourcesMap()["oci_core_route_table"]
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"compartment_id": "ocid1.compartment.oc1..standin",
+		"vcn_id":         "ocid1.vcn.oc1..standin",
+		"route_rules": []interface{}{
+			map[string]interface{}{"cidr_block": "0.0.0.0/0", "network_entity_id": "ocid1.internetgateway.oc1..available"},
+			map[string]interface{}{"cidr_block": "192.168.0.0/16", "network_entity_id": "ocid1.drg.oc1..deleted"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(nil, terraform.NewResourceConfig(raw))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// The route table isn't created, and the error names the rule.
+	_, err = r.Apply(nil, diff, clients)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "route_rules.1: DRG ocid1.drg.oc1..deleted does not exist")
+	}
+	assert.Equal(t, int32(0), atomic.LoadInt32(&created))
+}
+
+func TestRouteTableResource_updateKeepsUncheckedTargets(t *testing.T) {
+	var created int32
+	clients, closeServer, err := newStandInClient(routeTargetStandIn(&created), func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := resourcesMap()["oci_core_route_table"]
+	rules := []interface{}{
+		map[string]interface{}{"cidr_block": "192.168.0.0/16", "network_entity_id": "ocid1.drg.oc1..deleted"},
+	}
+	d := r.Data(&terraform.InstanceState{ID: "ocid1.routetable.oc1..standin"})
+	for k, v := range map[string]interface{}{
+		"compartment_id": "ocid1.compartment.oc1..standin",
+		"vcn_id":         "ocid1.vcn.oc1..standin",
+		"display_name":   "before",
+		"route_rules":    rules,
+	} {
+		if err := d.Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"compartment_id": "ocid1.compartment.oc1..standin",
+		"vcn_id":         "ocid1.vcn.oc1..standin",
+		"display_name":   "after",
+		"route_rules":    rules,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(d.State(), terraform.NewResourceConfig(raw))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// Renaming the route table doesn't fail on a target that has gone since
+	// its rule was added.
+	state, err := r.Apply(d.State(), diff, clients)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "after", state.Attributes["display_name"])
+	}
+}
@@ -44,7 +44,10 @@ func RouteTableDatasource() *schema.Resource {
 }
 
 func readRouteTables(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &RouteTableDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
@@ -3,6 +3,11 @@
 package provider
 
 import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/oracle/bmcs-go-sdk"
 
@@ -63,6 +68,90 @@ var icmpSchema = &schema.Schema{
 	},
 }
 
+// protocolSchema accepts either the IANA protocol number the service uses,
+// or the name of a protocol it is commonly known by, e.g. "tcp" for "6".
+var protocolSchema = &schema.Schema{
+	Type:     schema.TypeString,
+	Required: true,
+	DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+		return normalizeProtocol(old) == normalizeProtocol(new)
+	},
+}
+
+var protocolNumbers = map[string]string{
+	"icmp":   "1",
+	"tcp":    "6",
+	"udp":    "17",
+	"icmpv6": "58",
+}
+
+// normalizeProtocol returns the protocol number for a protocol name, and
+// anything else, e.g. a number or "all", as it is.
+func normalizeProtocol(protocol string) string {
+	if number, ok := protocolNumbers[strings.ToLower(protocol)]; ok {
+		return number
+	}
+	return strings.ToLower(protocol)
+}
+
+func egressSecurityRuleHash(v interface{}) int {
+	rule := v.(map[string]interface{})
+	return securityRuleHash(rule, rule["destination"].(string))
+}
+
+func ingressSecurityRuleHash(v interface{}) int {
+	rule := v.(map[string]interface{})
+	return securityRuleHash(rule, rule["source"].(string))
+}
+
+// securityRuleHash identifies a rule by what it allows, so that rules don't
+// depend on their order, and rules that only differ in how they're written,
+// e.g. "tcp" and "6", or empty and missing options, are the same.
+func securityRuleHash(rule map[string]interface{}, peer string) int {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("%s-%s-", normalizeProtocol(rule["protocol"].(string)), peer))
+	if stateless, ok := rule["stateless"].(bool); ok && stateless {
+		buf.WriteString("stateless-")
+	}
+	if l, ok := rule["icmp_options"].([]interface{}); ok && len(l) > 0 && l[0] != nil {
+		opts := l[0].(map[string]interface{})
+		buf.WriteString(fmt.Sprintf("icmp:%d:%d-", opts["type"], opts["code"]))
+	}
+	for _, key := range []string{"tcp_options", "udp_options"} {
+		if l, ok := rule[key].([]interface{}); ok {
+			if portRanges := portRangesHashKey(l); portRanges != "" {
+				buf.WriteString(fmt.Sprintf("%s:%s-", key, portRanges))
+			}
+		}
+	}
+	return hashcode.String(buf.String())
+}
+
+// portRangesHashKey writes the destination and source port ranges of TCP or
+// UDP options, leaving out ranges that aren't set.
+func portRangesHashKey(conf []interface{}) string {
+	if len(conf) == 0 || conf[0] == nil {
+		return ""
+	}
+	mapConf := conf[0].(map[string]interface{})
+	key := portRangeHashKey(mapConf)
+	if l, ok := mapConf["source_port_range"].([]interface{}); ok && len(l) > 0 && l[0] != nil {
+		if source := portRangeHashKey(l[0].(map[string]interface{})); source != "" {
+			key += "/" + source
+		}
+	}
+	return key
+}
+
+func portRangeHashKey(conf map[string]interface{}) string {
+	max, _ := conf["max"].(int)
+	min, _ := conf["min"].(int)
+	if max == 0 && min == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d..%d", min, max)
+}
+
 func DefaultSecurityListResource() *schema.Resource {
 	return &schema.Resource{
 		Importer: &schema.ResourceImporter{
@@ -80,21 +169,20 @@ func DefaultSecurityListResource() *schema.Resource {
 				Optional: true,
 			},
 			"egress_security_rules": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Required: true,
+				Set:      egressSecurityRuleHash,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"destination": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateCIDRBlock,
 						},
 						"icmp_options": icmpSchema,
-						"protocol": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"tcp_options": transportSchema,
-						"udp_options": transportSchema,
+						"protocol":     protocolSchema,
+						"tcp_options":  transportSchema,
+						"udp_options":  transportSchema,
 						"stateless": {
 							Type:     schema.TypeBool,
 							Optional: true,
@@ -103,6 +191,7 @@ func DefaultSecurityListResource() *schema.Resource {
 					},
 				},
 			},
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -113,18 +202,17 @@ func DefaultSecurityListResource() *schema.Resource {
 				ForceNew: true,
 			},
 			"ingress_security_rules": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Required: true,
+				Set:      ingressSecurityRuleHash,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"icmp_options": icmpSchema,
-						"protocol": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
+						"protocol":     protocolSchema,
 						"source": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateCIDRBlock,
 						},
 						"tcp_options": transportSchema,
 						"udp_options": transportSchema,
@@ -170,21 +258,20 @@ func SecurityListResource() *schema.Resource {
 				Optional: true,
 			},
 			"egress_security_rules": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
+				Set:      egressSecurityRuleHash,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"destination": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateCIDRBlock,
 						},
 						"icmp_options": icmpSchema,
-						"protocol": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"tcp_options": transportSchema,
-						"udp_options": transportSchema,
+						"protocol":     protocolSchema,
+						"tcp_options":  transportSchema,
+						"udp_options":  transportSchema,
 						"stateless": {
 							Type:     schema.TypeBool,
 							Optional: true,
@@ -193,23 +280,23 @@ func SecurityListResource() *schema.Resource {
 					},
 				},
 			},
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
 			"ingress_security_rules": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
+				Set:      ingressSecurityRuleHash,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"icmp_options": icmpSchema,
-						"protocol": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
+						"protocol":     protocolSchema,
 						"source": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateCIDRBlock,
 						},
 						"tcp_options": transportSchema,
 						"udp_options": transportSchema,
@@ -239,7 +326,10 @@ func SecurityListResource() *schema.Resource {
 }
 
 func createSecurityList(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	crd := &SecurityListResourceCrud{}
 	crd.D = d
 	crd.Client = client.client
@@ -247,7 +337,10 @@ func createSecurityList(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readSecurityList(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	crd := &SecurityListResourceCrud{}
 	crd.D = d
 	crd.Client = client.client
@@ -255,7 +348,10 @@ func readSecurityList(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func updateSecurityList(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	crd := &SecurityListResourceCrud{}
 	crd.D = d
 	crd.Client = client.client
@@ -263,7 +359,10 @@ func updateSecurityList(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteSecurityList(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	crd := &SecurityListResourceCrud{}
 	crd.D = d
 	crd.Client = client.clientWithoutNotFoundRetries
@@ -346,11 +445,21 @@ func (s *SecurityListResourceCrud) Update() (e error) {
 		opts.DisplayName = displayName.(string)
 	}
 
-	if egress := s.buildEgressRules(); egress != nil {
-		opts.EgressRules = egress
+	// Rules are only sent when they change, so that updating a list whose
+	// rules are in lifecycle.ignore_changes keeps the rules added to it by
+	// oci_core_security_list_rule.
+	if s.D.IsNewResource() || s.D.HasChange("egress_security_rules") {
+		opts.EgressRules = s.buildEgressRules()
 	}
-	if ingress := s.buildIngressRules(); ingress != nil {
-		opts.IngressRules = ingress
+	if s.D.IsNewResource() || s.D.HasChange("ingress_security_rules") {
+		opts.IngressRules = s.buildIngressRules()
+	}
+
+	// oci_core_security_list_rule changes the list's ETag whenever it adds or
+	// removes a rule, so only writes that replace the rules are conditional
+	// on it. Otherwise every update after a rule change would fail.
+	if opts.EgressRules != nil || opts.IngressRules != nil {
+		opts.IfMatch = s.IfMatch()
 	}
 
 	s.Res, e = s.Client.UpdateSecurityList(s.D.Id(), opts)
@@ -375,6 +484,7 @@ func (s *SecurityListResourceCrud) reset() (e error) {
 		IngressRules: []baremetal.IngressSecurityRule{},
 		EgressRules:  []baremetal.EgressSecurityRule{},
 	}
+	// Like Delete, this is not conditional on the ETag.
 
 	_, e = s.Client.UpdateSecurityList(s.D.Id(), opts)
 	return
@@ -389,48 +499,50 @@ func (s *SecurityListResourceCrud) Delete() (e error) {
 		return
 	}
 
+	// Not conditional on the ETag, which oci_core_security_list_rule resources
+	// destroyed just before this change.
 	return s.Client.DeleteSecurityList(s.D.Id(), nil)
 }
 
 func (s *SecurityListResourceCrud) buildEgressRules() (sdkRules []baremetal.EgressSecurityRule) {
 	sdkRules = []baremetal.EgressSecurityRule{}
-	for _, val := range s.D.Get("egress_security_rules").([]interface{}) {
-		confRule := val.(map[string]interface{})
-
-		sdkRule := baremetal.EgressSecurityRule{
-			Destination: confRule["destination"].(string),
-			ICMPOptions: s.buildICMPOptions(confRule),
-			Protocol:    confRule["protocol"].(string),
-			TCPOptions:  s.buildTCPOptions(confRule),
-			UDPOptions:  s.buildUDPOptions(confRule),
-			IsStateless: confRule["stateless"].(bool),
-		}
-
-		sdkRules = append(sdkRules, sdkRule)
+	for _, val := range s.D.Get("egress_security_rules").(*schema.Set).List() {
+		sdkRules = append(sdkRules, buildEgressRule(val.(map[string]interface{})))
 	}
 	return
 }
 
 func (s *SecurityListResourceCrud) buildIngressRules() (sdkRules []baremetal.IngressSecurityRule) {
 	sdkRules = []baremetal.IngressSecurityRule{}
-	for _, val := range s.D.Get("ingress_security_rules").([]interface{}) {
-		confRule := val.(map[string]interface{})
-
-		sdkRule := baremetal.IngressSecurityRule{
-			ICMPOptions: s.buildICMPOptions(confRule),
-			Protocol:    confRule["protocol"].(string),
-			Source:      confRule["source"].(string),
-			TCPOptions:  s.buildTCPOptions(confRule),
-			UDPOptions:  s.buildUDPOptions(confRule),
-			IsStateless: confRule["stateless"].(bool),
-		}
-
-		sdkRules = append(sdkRules, sdkRule)
+	for _, val := range s.D.Get("ingress_security_rules").(*schema.Set).List() {
+		sdkRules = append(sdkRules, buildIngressRule(val.(map[string]interface{})))
 	}
 	return
 }
 
-func (s *SecurityListResourceCrud) buildICMPOptions(conf map[string]interface{}) (opts *baremetal.ICMPOptions) {
+func buildEgressRule(confRule map[string]interface{}) baremetal.EgressSecurityRule {
+	return baremetal.EgressSecurityRule{
+		Destination: confRule["destination"].(string),
+		ICMPOptions: buildICMPOptions(confRule),
+		Protocol:    normalizeProtocol(confRule["protocol"].(string)),
+		TCPOptions:  buildTCPOptions(confRule),
+		UDPOptions:  buildUDPOptions(confRule),
+		IsStateless: confRule["stateless"].(bool),
+	}
+}
+
+func buildIngressRule(confRule map[string]interface{}) baremetal.IngressSecurityRule {
+	return baremetal.IngressSecurityRule{
+		ICMPOptions: buildICMPOptions(confRule),
+		Protocol:    normalizeProtocol(confRule["protocol"].(string)),
+		Source:      confRule["source"].(string),
+		TCPOptions:  buildTCPOptions(confRule),
+		UDPOptions:  buildUDPOptions(confRule),
+		IsStateless: confRule["stateless"].(bool),
+	}
+}
+
+func buildICMPOptions(conf map[string]interface{}) (opts *baremetal.ICMPOptions) {
 	l := conf["icmp_options"].([]interface{})
 	if len(l) > 0 {
 		confOpts := l[0].(map[string]interface{})
@@ -442,10 +554,10 @@ func (s *SecurityListResourceCrud) buildICMPOptions(conf map[string]interface{})
 	return
 }
 
-func (s *SecurityListResourceCrud) buildTCPOptions(conf map[string]interface{}) (opts *baremetal.TCPOptions) {
+func buildTCPOptions(conf map[string]interface{}) (opts *baremetal.TCPOptions) {
 	options := conf["tcp_options"].([]interface{})
 	if len(options) > 0 {
-		sourcePortRange, destinationPortRange := s.buildSourceAndDestinationPortRanges(options)
+		sourcePortRange, destinationPortRange := buildSourceAndDestinationPortRanges(options)
 		opts = &baremetal.TCPOptions{
 			DestinationPortRange: destinationPortRange,
 			SourcePortRange:      sourcePortRange,
@@ -454,10 +566,10 @@ func (s *SecurityListResourceCrud) buildTCPOptions(conf map[string]interface{})
 	return
 }
 
-func (s *SecurityListResourceCrud) buildUDPOptions(conf map[string]interface{}) (opts *baremetal.UDPOptions) {
+func buildUDPOptions(conf map[string]interface{}) (opts *baremetal.UDPOptions) {
 	options := conf["udp_options"].([]interface{})
 	if len(options) > 0 {
-		sourcePortRange, destinationPortRange := s.buildSourceAndDestinationPortRanges(options)
+		sourcePortRange, destinationPortRange := buildSourceAndDestinationPortRanges(options)
 		opts = &baremetal.UDPOptions{
 			DestinationPortRange: destinationPortRange,
 			SourcePortRange:      sourcePortRange,
@@ -487,7 +599,7 @@ func buildPortRange(conf []interface{}) (portRange *baremetal.PortRange) {
 	return
 }
 
-func (s *SecurityListResourceCrud) buildSourceAndDestinationPortRanges(conf []interface{}) (sourcePortRange, destinationPortRange *baremetal.PortRange) {
+func buildSourceAndDestinationPortRanges(conf []interface{}) (sourcePortRange, destinationPortRange *baremetal.PortRange) {
 	if len(conf) > 0 && conf[0] != nil {
 		mapConf := conf[0].(map[string]interface{})
 		sourcePortRange = buildPortRange(mapConf["source_port_range"].([]interface{}))
@@ -3,11 +3,16 @@
 package provider
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/oracle/bmcs-go-sdk"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -136,75 +141,91 @@ func (s *ResourceCoreSecurityListTestSuite) BuildTestsForFullConfig(resourceName
 	return []resource.TestCheckFunc{
 		resource.TestCheckResourceAttr(resourceName, prefix+"display_name", "-tf-security_list"),
 		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.#", "4"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.0.destination", "0.0.0.0/1"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.0.protocol", "6"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.0.stateless", "false"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.0.tcp_options.#", "0"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.0.udp_options.#", "0"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.0.icmp_options.#", "0"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.1.destination", "0.0.0.0/2"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.1.protocol", "1"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.1.stateless", "true"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.1.tcp_options.#", "0"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.1.udp_options.#", "0"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.1.icmp_options.#", "1"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.1.icmp_options.0.type", "3"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.1.icmp_options.0.code", "4"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.2.destination", "0.0.0.0/3"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.2.protocol", "6"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.2.stateless", "false"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.2.tcp_options.#", "1"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.2.tcp_options.0.min", "10"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.2.tcp_options.0.max", "11"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.2.tcp_options.0.source_port_range.0.min", "20"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.2.tcp_options.0.source_port_range.0.max", "21"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.2.udp_options.#", "0"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.2.icmp_options.#", "0"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.3.destination", "0.0.0.0/4"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.3.protocol", "17"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.3.stateless", "false"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.3.tcp_options.#", "0"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.3.udp_options.#", "1"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.3.udp_options.0.min", "30"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.3.udp_options.0.max", "31"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.3.udp_options.0.source_port_range.0.min", "40"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.3.udp_options.0.source_port_range.0.max", "41"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"egress_security_rules.3.icmp_options.#", "0"),
+		testCheckSecurityRule(resourceName, prefix+"egress_security_rules", map[string]string{
+			"destination":    "0.0.0.0/1",
+			"protocol":       "6",
+			"stateless":      "false",
+			"tcp_options.#":  "0",
+			"udp_options.#":  "0",
+			"icmp_options.#": "0",
+		}),
+		testCheckSecurityRule(resourceName, prefix+"egress_security_rules", map[string]string{
+			"destination":         "0.0.0.0/2",
+			"protocol":            "1",
+			"stateless":           "true",
+			"tcp_options.#":       "0",
+			"udp_options.#":       "0",
+			"icmp_options.#":      "1",
+			"icmp_options.0.type": "3",
+			"icmp_options.0.code": "4",
+		}),
+		testCheckSecurityRule(resourceName, prefix+"egress_security_rules", map[string]string{
+			"destination":                           "0.0.0.0/3",
+			"protocol":                              "6",
+			"stateless":                             "false",
+			"tcp_options.#":                         "1",
+			"tcp_options.0.min":                     "10",
+			"tcp_options.0.max":                     "11",
+			"tcp_options.0.source_port_range.0.min": "20",
+			"tcp_options.0.source_port_range.0.max": "21",
+			"udp_options.#":                         "0",
+			"icmp_options.#":                        "0",
+		}),
+		testCheckSecurityRule(resourceName, prefix+"egress_security_rules", map[string]string{
+			"destination":                           "0.0.0.0/4",
+			"protocol":                              "17",
+			"stateless":                             "false",
+			"tcp_options.#":                         "0",
+			"udp_options.#":                         "1",
+			"udp_options.0.min":                     "30",
+			"udp_options.0.max":                     "31",
+			"udp_options.0.source_port_range.0.min": "40",
+			"udp_options.0.source_port_range.0.max": "41",
+			"icmp_options.#":                        "0",
+		}),
 		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.#", "4"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.0.source", "0.0.0.0/5"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.0.protocol", "1"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.0.stateless", "false"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.0.tcp_options.#", "0"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.0.udp_options.#", "0"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.0.icmp_options.#", "0"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.1.source", "0.0.0.0/6"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.1.protocol", "1"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.1.stateless", "false"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.1.tcp_options.#", "0"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.1.udp_options.#", "0"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.1.icmp_options.#", "1"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.1.icmp_options.0.type", "3"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.1.icmp_options.0.code", "4"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.2.source", "0.0.0.0/7"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.2.protocol", "6"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.2.stateless", "true"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.2.tcp_options.#", "1"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.2.tcp_options.0.min", "50"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.2.tcp_options.0.max", "51"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.2.tcp_options.0.source_port_range.0.min", "60"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.2.tcp_options.0.source_port_range.0.max", "61"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.2.udp_options.#", "0"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.2.icmp_options.#", "0"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.3.source", "10.0.0.0/8"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.3.protocol", "17"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.3.stateless", "false"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.3.tcp_options.#", "0"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.3.udp_options.#", "1"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.3.udp_options.0.min", "70"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.3.udp_options.0.max", "71"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.3.udp_options.0.source_port_range.0.min", "80"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.3.udp_options.0.source_port_range.0.max", "81"),
-		resource.TestCheckResourceAttr(resourceName, prefix+"ingress_security_rules.3.icmp_options.#", "0"),
+		testCheckSecurityRule(resourceName, prefix+"ingress_security_rules", map[string]string{
+			"source":         "0.0.0.0/5",
+			"protocol":       "1",
+			"stateless":      "false",
+			"tcp_options.#":  "0",
+			"udp_options.#":  "0",
+			"icmp_options.#": "0",
+		}),
+		testCheckSecurityRule(resourceName, prefix+"ingress_security_rules", map[string]string{
+			"source":              "0.0.0.0/6",
+			"protocol":            "1",
+			"stateless":           "false",
+			"tcp_options.#":       "0",
+			"udp_options.#":       "0",
+			"icmp_options.#":      "1",
+			"icmp_options.0.type": "3",
+			"icmp_options.0.code": "4",
+		}),
+		testCheckSecurityRule(resourceName, prefix+"ingress_security_rules", map[string]string{
+			"source":                                "0.0.0.0/7",
+			"protocol":                              "6",
+			"stateless":                             "true",
+			"tcp_options.#":                         "1",
+			"tcp_options.0.min":                     "50",
+			"tcp_options.0.max":                     "51",
+			"tcp_options.0.source_port_range.0.min": "60",
+			"tcp_options.0.source_port_range.0.max": "61",
+			"udp_options.#":                         "0",
+			"icmp_options.#":                        "0",
+		}),
+		testCheckSecurityRule(resourceName, prefix+"ingress_security_rules", map[string]string{
+			"source":                                "10.0.0.0/8",
+			"protocol":                              "17",
+			"stateless":                             "false",
+			"tcp_options.#":                         "0",
+			"udp_options.#":                         "1",
+			"udp_options.0.min":                     "70",
+			"udp_options.0.max":                     "71",
+			"udp_options.0.source_port_range.0.min": "80",
+			"udp_options.0.source_port_range.0.max": "81",
+			"icmp_options.#":                        "0",
+		}),
 	}
 }
 
@@ -243,12 +264,14 @@ func (s *ResourceCoreSecurityListTestSuite) TestAccResourceCoreSecurityList_basi
 				`,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.#", "1"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.0.destination", "0.0.0.0/1"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.0.protocol", "6"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.0.stateless", "false"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.0.tcp_options.#", "0"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.0.udp_options.#", "0"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.0.icmp_options.#", "0"),
+					testCheckSecurityRule(s.ResourceName, "egress_security_rules", map[string]string{
+						"destination":    "0.0.0.0/1",
+						"protocol":       "6",
+						"stateless":      "false",
+						"tcp_options.#":  "0",
+						"udp_options.#":  "0",
+						"icmp_options.#": "0",
+					}),
 					resource.TestCheckResourceAttr(s.ResourceName, "ingress_security_rules.#", "0"),
 				),
 			},
@@ -302,24 +325,28 @@ func (s *ResourceCoreSecurityListTestSuite) TestAccResourceCoreSecurityList_basi
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.#", "2"),
 					resource.TestCheckResourceAttr(s.ResourceName, "ingress_security_rules.#", "0"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.0.destination", "0.0.0.0/3"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.0.protocol", "6"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.0.stateless", "false"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.0.tcp_options.#", "1"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.0.tcp_options.0.min", "0"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.0.tcp_options.0.max", "0"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.0.tcp_options.0.source_port_range.0.min", "20"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.0.tcp_options.0.source_port_range.0.max", "21"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.0.udp_options.#", "0"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.0.icmp_options.#", "0"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.1.destination", "0.0.0.0/4"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.1.protocol", "17"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.1.stateless", "false"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.1.tcp_options.#", "0"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.1.udp_options.#", "1"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.1.udp_options.0.min", "1"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.1.udp_options.0.max", "65535"),
-					resource.TestCheckResourceAttr(s.ResourceName, "egress_security_rules.1.udp_options.0.source_port_range.#", "0"),
+					testCheckSecurityRule(s.ResourceName, "egress_security_rules", map[string]string{
+						"destination":                           "0.0.0.0/3",
+						"protocol":                              "6",
+						"stateless":                             "false",
+						"tcp_options.#":                         "1",
+						"tcp_options.0.min":                     "0",
+						"tcp_options.0.max":                     "0",
+						"tcp_options.0.source_port_range.0.min": "20",
+						"tcp_options.0.source_port_range.0.max": "21",
+						"udp_options.#":                         "0",
+						"icmp_options.#":                        "0",
+					}),
+					testCheckSecurityRule(s.ResourceName, "egress_security_rules", map[string]string{
+						"destination":                       "0.0.0.0/4",
+						"protocol":                          "17",
+						"stateless":                         "false",
+						"tcp_options.#":                     "0",
+						"udp_options.#":                     "1",
+						"udp_options.0.min":                 "1",
+						"udp_options.0.max":                 "65535",
+						"udp_options.0.source_port_range.#": "0",
+					}),
 				),
 			},
 		},
@@ -417,11 +444,19 @@ func (s *ResourceCoreSecurityListTestSuite) TestAccResourceCoreSecurityList_defa
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr(s.DefaultResourceName, "display_name", "default-tf-security_list"),
 					resource.TestCheckResourceAttr(s.DefaultResourceName, "egress_security_rules.#", "1"),
-					resource.TestCheckResourceAttr(s.DefaultResourceName, "egress_security_rules.0.stateless", "false"),
+					testCheckSecurityRule(s.DefaultResourceName, "egress_security_rules", map[string]string{
+						"stateless": "false",
+					}),
 					resource.TestCheckResourceAttr(s.DefaultResourceName, "ingress_security_rules.#", "3"),
-					resource.TestCheckResourceAttr(s.DefaultResourceName, "ingress_security_rules.0.icmp_options.0.type", "3"),
-					resource.TestCheckResourceAttr(s.DefaultResourceName, "ingress_security_rules.1.tcp_options.0.max", "80"),
-					resource.TestCheckResourceAttr(s.DefaultResourceName, "ingress_security_rules.2.udp_options.0.max", "320"),
+					testCheckSecurityRule(s.DefaultResourceName, "ingress_security_rules", map[string]string{
+						"icmp_options.0.type": "3",
+					}),
+					testCheckSecurityRule(s.DefaultResourceName, "ingress_security_rules", map[string]string{
+						"tcp_options.0.max": "80",
+					}),
+					testCheckSecurityRule(s.DefaultResourceName, "ingress_security_rules", map[string]string{
+						"udp_options.0.max": "320",
+					}),
 				),
 			},
 			// Update
@@ -466,14 +501,23 @@ func (s *ResourceCoreSecurityListTestSuite) TestAccResourceCoreSecurityList_defa
 				`,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr(s.DefaultResourceName, "display_name", "default-tf-security_list-updated"),
-					resource.TestCheckResourceAttr(s.DefaultResourceName, "egress_security_rules.0.protocol", "17"),
-					resource.TestCheckResourceAttr(s.DefaultResourceName, "egress_security_rules.0.stateless", "true"),
-					resource.TestCheckResourceAttr(s.DefaultResourceName, "ingress_security_rules.0.stateless", "true"),
-					resource.TestCheckResourceAttr(s.DefaultResourceName, "ingress_security_rules.0.icmp_options.0.type", "5"),
-					resource.TestCheckResourceAttr(s.DefaultResourceName, "ingress_security_rules.1.tcp_options.0.source_port_range.0.max", "100"),
-					resource.TestCheckResourceAttr(s.DefaultResourceName, "ingress_security_rules.1.stateless", "true"),
-					resource.TestCheckResourceAttr(s.DefaultResourceName, "ingress_security_rules.2.stateless", "true"),
-					resource.TestCheckNoResourceAttr(s.DefaultResourceName, "ingress_security_rules.2.udp_options"),
+					testCheckSecurityRule(s.DefaultResourceName, "egress_security_rules", map[string]string{
+						"protocol":  "17",
+						"stateless": "true",
+					}),
+					testCheckSecurityRule(s.DefaultResourceName, "ingress_security_rules", map[string]string{
+						"stateless":           "true",
+						"icmp_options.0.type": "5",
+					}),
+					testCheckSecurityRule(s.DefaultResourceName, "ingress_security_rules", map[string]string{
+						"tcp_options.0.source_port_range.0.max": "100",
+						"stateless":                             "true",
+					}),
+					testCheckSecurityRule(s.DefaultResourceName, "ingress_security_rules", map[string]string{
+						"source":        "10.0.0.0/16",
+						"stateless":     "true",
+						"udp_options.#": "0",
+					}),
 				),
 			},
 			// Verify removing the default resource
@@ -487,11 +531,19 @@ func (s *ResourceCoreSecurityListTestSuite) TestAccResourceCoreSecurityList_defa
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr(s.DefaultResourceName, "display_name", "default-tf-security_list"),
 					resource.TestCheckResourceAttr(s.DefaultResourceName, "egress_security_rules.#", "1"),
-					resource.TestCheckResourceAttr(s.DefaultResourceName, "egress_security_rules.0.stateless", "false"),
+					testCheckSecurityRule(s.DefaultResourceName, "egress_security_rules", map[string]string{
+						"stateless": "false",
+					}),
 					resource.TestCheckResourceAttr(s.DefaultResourceName, "ingress_security_rules.#", "3"),
-					resource.TestCheckResourceAttr(s.DefaultResourceName, "ingress_security_rules.0.icmp_options.0.type", "3"),
-					resource.TestCheckResourceAttr(s.DefaultResourceName, "ingress_security_rules.1.tcp_options.0.max", "80"),
-					resource.TestCheckResourceAttr(s.DefaultResourceName, "ingress_security_rules.2.udp_options.0.max", "320"),
+					testCheckSecurityRule(s.DefaultResourceName, "ingress_security_rules", map[string]string{
+						"icmp_options.0.type": "3",
+					}),
+					testCheckSecurityRule(s.DefaultResourceName, "ingress_security_rules", map[string]string{
+						"tcp_options.0.max": "80",
+					}),
+					testCheckSecurityRule(s.DefaultResourceName, "ingress_security_rules", map[string]string{
+						"udp_options.0.max": "320",
+					}),
 				),
 			},
 			// Verify lists can be cleared out. Also try adding an additional security list.
@@ -520,6 +572,253 @@ func (s *ResourceCoreSecurityListTestSuite) TestAccResourceCoreSecurityList_defa
 	})
 }
 
+// testCheckSecurityRule checks that one of the rules under key, which may be
+// a list or a set, has all of attrs.
+func testCheckSecurityRule(name, key string, attrs map[string]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		rules := map[string]map[string]string{}
+		for k, v := range rs.Primary.Attributes {
+			if !strings.HasPrefix(k, key+".") {
+				continue
+			}
+			parts := strings.SplitN(strings.TrimPrefix(k, key+"."), ".", 2)
+			if len(parts) < 2 {
+				continue
+			}
+			if rules[parts[0]] == nil {
+				rules[parts[0]] = map[string]string{}
+			}
+			rules[parts[0]][parts[1]] = v
+		}
+	nextRule:
+		for _, rule := range rules {
+			for k, v := range attrs {
+				got, ok := rule[k]
+				// Empty nested blocks may be left out.
+				if !ok && strings.HasSuffix(k, ".#") && v == "0" {
+					continue
+				}
+				if got != v {
+					continue nextRule
+				}
+			}
+			return nil
+		}
+		return fmt.Errorf("%s: no rule in %s has %v", name, key, attrs)
+	}
+}
+
 func TestResourceCoreSecurityListTestSuite(t *testing.T) {
 	suite.Run(t, new(ResourceCoreSecurityListTestSuite))
 }
+
+func TestSecurityRuleHash(t *testing.T) {
+	rule := func(protocol string, tcpOptions []interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"destination":  "0.0.0.0/0",
+			"protocol":     protocol,
+			"stateless":    false,
+			"icmp_options": []interface{}{},
+			"tcp_options":  tcpOptions,
+			"udp_options":  []interface{}{},
+		}
+	}
+	ports := func(min, max, sourceMin, sourceMax int) []interface{} {
+		return []interface{}{map[string]interface{}{
+			"min": min,
+			"max": max,
+			"source_port_range": []interface{}{map[string]interface{}{
+				"min": sourceMin,
+				"max": sourceMax,
+			}},
+		}}
+	}
+
+	// Protocol names and numbers are the same, and so are empty and missing
+	// options.
+	assert.Equal(t, egressSecurityRuleHash(rule("6", nil)), egressSecurityRuleHash(rule("tcp", nil)))
+	assert.Equal(t, egressSecurityRuleHash(rule("6", nil)), egressSecurityRuleHash(rule("TCP", []interface{}{map[string]interface{}{}})))
+	assert.Equal(t, egressSecurityRuleHash(rule("6", nil)), egressSecurityRuleHash(rule("6", ports(0, 0, 0, 0))))
+	assert.Equal(t, egressSecurityRuleHash(rule("all", nil)), egressSecurityRuleHash(rule("ALL", nil)))
+
+	// Anything the rule allows tells rules apart.
+	assert.NotEqual(t, egressSecurityRuleHash(rule("6", nil)), egressSecurityRuleHash(rule("17", nil)))
+	assert.NotEqual(t, egressSecurityRuleHash(rule("6", ports(22, 22, 0, 0))), egressSecurityRuleHash(rule("6", ports(0, 0, 22, 22))))
+	assert.NotEqual(t, egressSecurityRuleHash(rule("6", ports(22, 22, 0, 0))), egressSecurityRuleHash(rule("6", ports(22, 23, 0, 0))))
+	stateless := rule("6", nil)
+	stateless["stateless"] = true
+	assert.NotEqual(t, egressSecurityRuleHash(rule("6", nil)), egressSecurityRuleHash(stateless))
+}
+
+func TestSecurityListRuleDiffs(t *testing.T) {
+	r := resourcesMap()["oci_core_security_list"]
+
+	// The state is what the service returns, with protocol numbers.
+	d := r.Data(&terraform.InstanceState{ID: "ocid1.securitylist.oc1..standin"})
+	egress, ingress := buildConfRuleLists(&baremetal.SecurityList{
+		EgressSecurityRules: []baremetal.EgressSecurityRule{
+			{Destination: "0.0.0.0/0", Protocol: "all"},
+		},
+		IngressSecurityRules: []baremetal.IngressSecurityRule{
+			{Source: "0.0.0.0/0", Protocol: "6", TCPOptions: &baremetal.TCPOptions{DestinationPortRange: &baremetal.PortRange{Min: 22, Max: 22}}},
+			{Source: "10.0.0.0/16", Protocol: "1", ICMPOptions: &baremetal.ICMPOptions{Type: 3, Code: 4}},
+		},
+	})
+	for k, v := range map[string]interface{}{
+		"compartment_id":         "ocid1.compartment.oc1..standin",
+		"vcn_id":                 "ocid1.vcn.oc1..standin",
+		"display_name":           "standin",
+		"egress_security_rules":  egress,
+		"ingress_security_rules": ingress,
+	} {
+		if err := d.Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	state := d.State()
+
+	diff := func(ingressRules ...map[string]interface{}) *terraform.InstanceDiff {
+		rules := make([]interface{}, len(ingressRules))
+		for i, rule := range ingressRules {
+			rules[i] = rule
+		}
+		raw, err := config.NewRawConfig(map[string]interface{}{
+			"compartment_id": "ocid1.compartment.oc1..standin",
+			"vcn_id":         "ocid1.vcn.oc1..standin",
+			"display_name":   "standin",
+			"egress_security_rules": []interface{}{
+				map[string]interface{}{"destination": "0.0.0.0/0", "protocol": "all"},
+			},
+			"ingress_security_rules": rules,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := r.Diff(state, terraform.NewResourceConfig(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	ssh := map[string]interface{}{
+		"source":      "0.0.0.0/0",
+		"protocol":    "tcp",
+		"tcp_options": []interface{}{map[string]interface{}{"min": 22, "max": 22}},
+	}
+	icmp := map[string]interface{}{
+		"source":       "10.0.0.0/16",
+		"protocol":     "1",
+		"icmp_options": []interface{}{map[string]interface{}{"type": 3, "code": 4}},
+	}
+	https := map[string]interface{}{
+		"source":      "0.0.0.0/0",
+		"protocol":    "6",
+		"tcp_options": []interface{}{map[string]interface{}{"min": 443, "max": 443}},
+	}
+
+	// Reordered rules, written with protocol names, are no change.
+	assert.True(t, diff(icmp, ssh).Empty())
+
+	// Adding and removing a rule only touches that rule.
+	res := diff(ssh, https)
+	if assert.False(t, res.Empty()) {
+		// Rules that stay the same are listed with their old values, so rules
+		// are told apart by their source.
+		added, removed := map[string]bool{}, map[string]bool{}
+		for k, attr := range res.Attributes {
+			if !strings.HasPrefix(k, "ingress_security_rules.") {
+				t.Errorf("unexpected change of %s", k)
+				continue
+			}
+			if !strings.HasSuffix(k, ".source") {
+				continue
+			}
+			hash := strings.Split(k, ".")[1]
+			switch {
+			case attr.NewRemoved:
+				removed[hash] = true
+			case attr.Old == "":
+				added[hash] = true
+			}
+		}
+		assert.Len(t, added, 1)
+		assert.Len(t, removed, 1)
+		for hash := range added {
+			assert.Equal(t, "443", res.Attributes["ingress_security_rules."+hash+".tcp_options.0.max"].New)
+		}
+		for hash := range removed {
+			assert.Equal(t, "10.0.0.0/16", res.Attributes["ingress_security_rules."+hash+".source"].Old)
+		}
+	}
+}
+
+// securityListWithRuleConfig is a security list whose rules are in
+// lifecycle.ignore_changes, named displayName, with the rules in extra added
+// by oci_core_security_list_rule.
+func securityListWithRuleConfig(displayName, extra string) string {
+	return testProviderConfig() + `
+	resource "oci_core_security_list" "t" {
+		compartment_id = "ocid1.compartment.oc1..standin"
+		vcn_id = "ocid1.vcn.oc1..standin"
+		display_name = "` + displayName + `"
+		ingress_security_rules = {
+			protocol = "6"
+			source = "0.0.0.0/0"
+			tcp_options {
+				"min" = 22
+				"max" = 22
+			}
+		}
+		lifecycle {
+			ignore_changes = ["ingress_security_rules", "egress_security_rules"]
+		}
+	}` + extra
+}
+
+const securityListHTTPSRuleConfig = `
+	resource "oci_core_security_list_rule" "t" {
+		security_list_id = "${oci_core_security_list.t.id}"
+		direction = "INGRESS"
+		protocol = "tcp"
+		source = "0.0.0.0/0"
+		tcp_options {
+			"min" = 443
+			"max" = 443
+		}
+	}`
+
+func TestSecurityListResource_destroyWithRules(t *testing.T) {
+	standIn := &securityListStandIn{list: baremetal.SecurityList{ID: "ocid1.securitylist.oc1..standin"}}
+	clients, closeServer, err := newStandInClient(standIn.ServeHTTP, func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	// Adding and removing the rule changes the list's ETag after the list
+	// last read it, which renaming and destroying the list don't depend on.
+	resource.UnitTest(t, resource.TestCase{
+		Providers: standInProviders(clients),
+		Steps: []resource.TestStep{
+			{
+				Config: securityListWithRuleConfig("before", securityListHTTPSRuleConfig),
+			},
+			{
+				Config: securityListWithRuleConfig("after", securityListHTTPSRuleConfig),
+				Check:  resource.TestCheckResourceAttr("oci_core_security_list.t", "display_name", "after"),
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			if standIn.list.State != baremetal.ResourceTerminated {
+				return fmt.Errorf("Security list is %s", standIn.list.State)
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,335 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/oracle/bmcs-go-sdk"
+
+	"github.com/oracle/terraform-provider-oci/crud"
+)
+
+const (
+	securityRuleDirectionIngress = "INGRESS"
+	securityRuleDirectionEgress  = "EGRESS"
+)
+
+// SecurityListRuleResource manages a single rule of a security list that
+// other configurations also add rules to. The list is updated by reading it,
+// adding or removing the rule, and writing it back if it hasn't changed in
+// the meantime.
+//
+// A security list whose rules are managed this way should have its rules in
+// lifecycle.ignore_changes, if it is managed by oci_core_security_list too.
+func SecurityListRuleResource() *schema.Resource {
+	return &schema.Resource{
+		Importer: &schema.ResourceImporter{
+			State: importSecurityListRule,
+		},
+		Timeouts: crud.DefaultTimeout,
+		Create:   createSecurityListRule,
+		Read:     readSecurityListRule,
+		Delete:   deleteSecurityListRule,
+		Schema: map[string]*schema.Schema{
+			"destination": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateCIDRBlock,
+			},
+			"direction": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					securityRuleDirectionIngress,
+					securityRuleDirectionEgress,
+				}, false),
+			},
+			"icmp_options": forceNewSchema(icmpSchema),
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"protocol": forceNewSchema(protocolSchema),
+			"security_list_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"source": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateCIDRBlock,
+			},
+			"stateless": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+			"tcp_options": forceNewSchema(transportSchema),
+			"udp_options": forceNewSchema(transportSchema),
+		},
+	}
+}
+
+// forceNewSchema copies s, with ForceNew set on it and on the fields of its
+// nested blocks.
+func forceNewSchema(s *schema.Schema) *schema.Schema {
+	c := *s
+	c.ForceNew = true
+	if r, ok := s.Elem.(*schema.Resource); ok {
+		fields := make(map[string]*schema.Schema, len(r.Schema))
+		for k, v := range r.Schema {
+			fields[k] = forceNewSchema(v)
+		}
+		c.Elem = &schema.Resource{Schema: fields}
+	}
+	return &c
+}
+
+// importSecurityListRule takes over a rule that is already in a security list,
+// from an ID of the form <security list OCID>/<direction>/<hash>, as in the
+// error that creating a duplicate rule fails with.
+func importSecurityListRule(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) != 3 || (parts[1] != securityRuleDirectionIngress && parts[1] != securityRuleDirectionEgress) {
+		return nil, fmt.Errorf("Security list rule ID %q is not of the form <security_list_id>/<INGRESS|EGRESS>/<hash>", d.Id())
+	}
+	listID, direction := parts[0], parts[1]
+	hash, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("Security list rule ID %q does not end in a rule hash: %s", d.Id(), err)
+	}
+
+	client, err := clientsForRegion(d, m)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.client.GetSecurityList(listID)
+	if err != nil {
+		return nil, err
+	}
+
+	egress, ingress := buildConfRuleLists(list)
+	rules, ruleHash, address := egress, egressSecurityRuleHash, "destination"
+	if direction == securityRuleDirectionIngress {
+		rules, ruleHash, address = ingress, ingressSecurityRuleHash, "source"
+	}
+	for _, rule := range rules {
+		if ruleHash(rule) != hash {
+			continue
+		}
+		d.Set("security_list_id", listID)
+		d.Set("direction", direction)
+		for _, k := range []string{address, "icmp_options", "protocol", "stateless", "tcp_options", "udp_options"} {
+			if v, ok := rule[k]; ok {
+				if err := d.Set(k, v); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return []*schema.ResourceData{d}, nil
+	}
+	return nil, fmt.Errorf("Security list %s has no %s rule with hash %d", listID, direction, hash)
+}
+
+func createSecurityListRule(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
+	crd := &SecurityListRuleResourceCrud{}
+	crd.D = d
+	crd.Client = client.client
+	return crud.CreateResource(d, crd)
+}
+
+func readSecurityListRule(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
+	crd := &SecurityListRuleResourceCrud{}
+	crd.D = d
+	crd.Client = client.clientWithoutNotFoundRetries
+	return crud.ReadResource(crd)
+}
+
+func deleteSecurityListRule(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
+	crd := &SecurityListRuleResourceCrud{}
+	crd.D = d
+	crd.Client = client.clientWithoutNotFoundRetries
+	return crud.DeleteResource(d, crd)
+}
+
+// SecurityListRuleResourceCrud adds and removes a rule of the security list
+// in List. The rule is identified by its hash, as in the rule sets of
+// oci_core_security_list.
+type SecurityListRuleResourceCrud struct {
+	crud.BaseCrud
+	List *baremetal.SecurityList
+}
+
+func (s *SecurityListRuleResourceCrud) ID() string {
+	return fmt.Sprintf("%s/%s/%d", s.D.Get("security_list_id").(string), s.D.Get("direction").(string), s.hash())
+}
+
+func (s *SecurityListRuleResourceCrud) Create() (e error) {
+	direction := s.D.Get("direction").(string)
+	if direction == securityRuleDirectionIngress && s.D.Get("source").(string) == "" {
+		return fmt.Errorf("source is required for %s rules", direction)
+	}
+	if direction == securityRuleDirectionEgress && s.D.Get("destination").(string) == "" {
+		return fmt.Errorf("destination is required for %s rules", direction)
+	}
+
+	return s.updateRules(func(opts *baremetal.UpdateSecurityListOptions) (bool, error) {
+		if s.hasRule() {
+			return false, fmt.Errorf("Security list %s already has a duplicate rule %s, which allows the same traffic; import it, or remove it from the list or from the configuration", s.List.ID, s.ID())
+		}
+		if direction == securityRuleDirectionIngress {
+			opts.IngressRules = append(s.List.IngressSecurityRules, buildIngressRule(s.confRule()))
+		} else {
+			opts.EgressRules = append(s.List.EgressSecurityRules, buildEgressRule(s.confRule()))
+		}
+		return true, nil
+	})
+}
+
+func (s *SecurityListRuleResourceCrud) Get() (e error) {
+	if s.List, e = s.Client.GetSecurityList(s.D.Get("security_list_id").(string)); e != nil {
+		return
+	}
+	if !s.hasRule() {
+		return crud.NewMissingResourceError("Rule %s is not in security list %s", s.D.Id(), s.List.ID)
+	}
+	return
+}
+
+func (s *SecurityListRuleResourceCrud) SetData() {}
+
+// Delete removes the first rule of the list with the same hash, i.e. that
+// allows the same traffic, leaving any others in place.
+func (s *SecurityListRuleResourceCrud) Delete() (e error) {
+	return s.updateRules(func(opts *baremetal.UpdateSecurityListOptions) (bool, error) {
+		hash := s.hash()
+		removed := false
+		if s.D.Get("direction").(string) == securityRuleDirectionIngress {
+			_, ingress := buildConfRuleLists(s.List)
+			opts.IngressRules = []baremetal.IngressSecurityRule{}
+			for i, rule := range s.List.IngressSecurityRules {
+				if !removed && ingressSecurityRuleHash(ingress[i]) == hash {
+					removed = true
+					continue
+				}
+				opts.IngressRules = append(opts.IngressRules, rule)
+			}
+			return removed, nil
+		}
+		egress, _ := buildConfRuleLists(s.List)
+		opts.EgressRules = []baremetal.EgressSecurityRule{}
+		for i, rule := range s.List.EgressSecurityRules {
+			if !removed && egressSecurityRuleHash(egress[i]) == hash {
+				removed = true
+				continue
+			}
+			opts.EgressRules = append(opts.EgressRules, rule)
+		}
+		return removed, nil
+	})
+}
+
+// updateRules reads the security list into s.List, lets modify set the rules
+// to write back, and writes them if modify reports a change. An error from
+// modify fails the update without writing. The write is conditional on the
+// list's ETag, and is retried from the read if another update got in between,
+// until the timeout or until Terraform is interrupted.
+func (s *SecurityListRuleResourceCrud) updateRules(modify func(*baremetal.UpdateSecurityListOptions) (bool, error)) error {
+	timeout := s.D.Timeout(schema.TimeoutCreate)
+	if s.D.Id() != "" {
+		timeout = s.D.Timeout(schema.TimeoutDelete)
+	}
+	return crud.Retry(s, timeout, func() *resource.RetryError {
+		list, err := s.Client.GetSecurityList(s.D.Get("security_list_id").(string))
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		s.List = list
+
+		opts := &baremetal.UpdateSecurityListOptions{}
+		opts.IfMatch = list.ETag
+		changed, err := modify(opts)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if !changed {
+			return nil
+		}
+		if s.List, err = s.Client.UpdateSecurityList(list.ID, opts); err != nil {
+			if crud.IsPreconditionFailed(err) {
+				log.Printf("[DEBUG] Security list %q changed while updating its rules, retrying: %v", list.ID, err)
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+}
+
+// confRule is the rule as an element of the rule sets of
+// oci_core_security_list.
+func (s *SecurityListRuleResourceCrud) confRule() map[string]interface{} {
+	rule := map[string]interface{}{
+		"icmp_options": s.D.Get("icmp_options"),
+		"protocol":     s.D.Get("protocol"),
+		"stateless":    s.D.Get("stateless"),
+		"tcp_options":  s.D.Get("tcp_options"),
+		"udp_options":  s.D.Get("udp_options"),
+	}
+	if s.D.Get("direction").(string) == securityRuleDirectionIngress {
+		rule["source"] = s.D.Get("source")
+	} else {
+		rule["destination"] = s.D.Get("destination")
+	}
+	return rule
+}
+
+func (s *SecurityListRuleResourceCrud) hash() int {
+	if s.D.Get("direction").(string) == securityRuleDirectionIngress {
+		return ingressSecurityRuleHash(s.confRule())
+	}
+	return egressSecurityRuleHash(s.confRule())
+}
+
+func (s *SecurityListRuleResourceCrud) hasRule() bool {
+	hash := s.hash()
+	egress, ingress := buildConfRuleLists(s.List)
+	if s.D.Get("direction").(string) == securityRuleDirectionIngress {
+		for _, rule := range ingress {
+			if ingressSecurityRuleHash(rule) == hash {
+				return true
+			}
+		}
+		return false
+	}
+	for _, rule := range egress {
+		if egressSecurityRuleHash(rule) == hash {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,488 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/oracle/bmcs-go-sdk"
+
+	"github.com/oracle/terraform-provider-oci/crud"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResourceCoreSecurityListRuleTestSuite struct {
+	suite.Suite
+	Providers    map[string]terraform.ResourceProvider
+	Config       string
+	ResourceName string
+}
+
+func (s *ResourceCoreSecurityListRuleTestSuite) SetupTest() {
+	s.Providers = testAccProviders
+	s.Config = testProviderConfig() + `
+	resource "oci_core_virtual_network" "t" {
+		cidr_block = "10.0.0.0/16"
+		compartment_id = "${var.compartment_id}"
+		display_name = "-tf-vcn"
+	}
+
+	resource "oci_core_security_list" "t" {
+		compartment_id = "${var.compartment_id}"
+		display_name = "-tf-security_list"
+		vcn_id = "${oci_core_virtual_network.t.id}"
+		ingress_security_rules = {
+			protocol = "6"
+			source = "0.0.0.0/0"
+			tcp_options {
+				"min" = 22
+				"max" = 22
+			}
+		}
+		lifecycle {
+			ignore_changes = ["ingress_security_rules", "egress_security_rules"]
+		}
+	}`
+	s.ResourceName = "oci_core_security_list_rule.t"
+}
+
+func (s *ResourceCoreSecurityListRuleTestSuite) TestAccResourceCoreSecurityListRule_basic() {
+	resource.Test(s.T(), resource.TestCase{
+		Providers: s.Providers,
+		Steps: []resource.TestStep{
+			{
+				Config: s.Config + `
+				resource "oci_core_security_list_rule" "t" {
+					security_list_id = "${oci_core_security_list.t.id}"
+					direction = "INGRESS"
+					protocol = "tcp"
+					source = "0.0.0.0/0"
+					tcp_options {
+						"min" = 443
+						"max" = 443
+					}
+				}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(s.ResourceName, "id"),
+					resource.TestCheckResourceAttr(s.ResourceName, "protocol", "tcp"),
+				),
+			},
+			// Refreshing the list with the added rule is no change.
+			{
+				Config: s.Config + `
+				resource "oci_core_security_list_rule" "t" {
+					security_list_id = "${oci_core_security_list.t.id}"
+					direction = "INGRESS"
+					protocol = "tcp"
+					source = "0.0.0.0/0"
+					tcp_options {
+						"min" = 443
+						"max" = 443
+					}
+				}`,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
+func TestResourceCoreSecurityListRuleTestSuite(t *testing.T) {
+	suite.Run(t, new(ResourceCoreSecurityListRuleTestSuite))
+}
+
+// securityListStandIn serves a security list, and rejects updates and
+// deletes whose If-Match isn't its current ETag. Rules in concurrent are
+// added by another writer just before the next update. A create replaces the
+// list, which keeps its ID.
+type securityListStandIn struct {
+	sync.Mutex
+	list       baremetal.SecurityList
+	etag       int
+	concurrent []baremetal.IngressSecurityRule
+	updates    int
+}
+
+func (s *securityListStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	w.Header().Set("content-type", "application/json")
+	if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/securityLists") {
+		id := s.list.ID
+		s.list = baremetal.SecurityList{}
+		json.NewDecoder(r.Body).Decode(&s.list)
+		s.list.ID = id
+		s.list.State = baremetal.ResourceAvailable
+		s.etag++
+		w.Header().Set("ETag", fmt.Sprintf("etag-%d", s.etag))
+		json.NewEncoder(w).Encode(s.list)
+		return
+	}
+	if !strings.HasSuffix(r.URL.Path, "/securityLists/"+s.list.ID) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code":"NotAuthorizedOrNotFound","message":"not found"}`))
+		return
+	}
+	if r.Method == http.MethodDelete {
+		if !s.ifMatches(w, r) {
+			return
+		}
+		s.list.State = baremetal.ResourceTerminated
+		s.etag++
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method == http.MethodPut {
+		s.updates++
+		if len(s.concurrent) > 0 {
+			s.list.IngressSecurityRules = append(s.list.IngressSecurityRules, s.concurrent...)
+			s.concurrent = nil
+			s.etag++
+		}
+		if !s.ifMatches(w, r) {
+			return
+		}
+		var body struct {
+			DisplayName  string                           `json:"displayName"`
+			EgressRules  *[]baremetal.EgressSecurityRule  `json:"egressSecurityRules"`
+			IngressRules *[]baremetal.IngressSecurityRule `json:"ingressSecurityRules"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.DisplayName != "" {
+			s.list.DisplayName = body.DisplayName
+		}
+		if body.EgressRules != nil {
+			s.list.EgressSecurityRules = *body.EgressRules
+		}
+		if body.IngressRules != nil {
+			s.list.IngressSecurityRules = *body.IngressRules
+		}
+		s.etag++
+	}
+	w.Header().Set("ETag", fmt.Sprintf("etag-%d", s.etag))
+	json.NewEncoder(w).Encode(s.list)
+}
+
+// ifMatches fails the request with a 412 if it has an If-Match that isn't the
+// list's current ETag.
+func (s *securityListStandIn) ifMatches(w http.ResponseWriter, r *http.Request) bool {
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && ifMatch != fmt.Sprintf("etag-%d", s.etag) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		w.Write([]byte(`{"code":"NoEtagMatch","message":"The resource's etag does not match"}`))
+		return false
+	}
+	return true
+}
+
+func TestSecurityListRuleResource(t *testing.T) {
+	ssh := baremetal.IngressSecurityRule{Source: "0.0.0.0/0", Protocol: "6", TCPOptions: &baremetal.TCPOptions{DestinationPortRange: &baremetal.PortRange{Min: 22, Max: 22}}}
+	icmp := baremetal.IngressSecurityRule{Source: "10.0.0.0/16", Protocol: "1", ICMPOptions: &baremetal.ICMPOptions{Type: 3, Code: 4}}
+	https := baremetal.IngressSecurityRule{Source: "0.0.0.0/0", Protocol: "6", TCPOptions: &baremetal.TCPOptions{DestinationPortRange: &baremetal.PortRange{Min: 443, Max: 443}}}
+	standIn := &securityListStandIn{
+		list: baremetal.SecurityList{
+			ID:                   "ocid1.securitylist.oc1..standin",
+			State:                baremetal.ResourceAvailable,
+			EgressSecurityRules:  []baremetal.EgressSecurityRule{{Destination: "0.0.0.0/0", Protocol: "all"}},
+			IngressSecurityRules: []baremetal.IngressSecurityRule{ssh},
+		},
+		concurrent: []baremetal.IngressSecurityRule{icmp},
+	}
+	clients, closeServer, err := newStandInClient(standIn.ServeHTTP, func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := resourcesMap()["oci_core_security_list_rule"]
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"security_list_id": "ocid1.securitylist.oc1..standin",
+		"direction":        "INGRESS",
+		"protocol":         "tcp",
+		"source":           "0.0.0.0/0",
+		"tcp_options":      []interface{}{map[string]interface{}{"min": 443, "max": 443}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(nil, terraform.NewResourceConfig(raw))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// The rule is added to the rules of the list, including those another
+	// writer added in the meantime.
+	state, err := r.Apply(nil, diff, clients)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, strings.HasPrefix(state.ID, "ocid1.securitylist.oc1..standin/INGRESS/"))
+	assert.Equal(t, 2, standIn.updates)
+	assert.Equal(t, []baremetal.EgressSecurityRule{{Destination: "0.0.0.0/0", Protocol: "all"}}, standIn.list.EgressSecurityRules)
+	assert.Equal(t, []baremetal.IngressSecurityRule{ssh, icmp, https}, standIn.list.IngressSecurityRules)
+
+	state, err = r.Refresh(state, clients)
+	if assert.Nil(t, err) && assert.NotNil(t, state) {
+		assert.True(t, strings.HasPrefix(state.ID, "ocid1.securitylist.oc1..standin/INGRESS/"))
+	}
+
+	// Destroying it only removes that rule.
+	_, err = r.Apply(state, &terraform.InstanceDiff{Destroy: true}, clients)
+	assert.Nil(t, err)
+	assert.Equal(t, []baremetal.IngressSecurityRule{ssh, icmp}, standIn.list.IngressSecurityRules)
+
+	// A rule removed from the list is gone.
+	state, err = r.Refresh(state, clients)
+	assert.Nil(t, err)
+	assert.True(t, state == nil || state.ID == "")
+}
+
+func TestSecurityListRuleResource_duplicate(t *testing.T) {
+	https := baremetal.IngressSecurityRule{Source: "0.0.0.0/0", Protocol: "6", TCPOptions: &baremetal.TCPOptions{DestinationPortRange: &baremetal.PortRange{Min: 443, Max: 443}}}
+	standIn := &securityListStandIn{list: baremetal.SecurityList{
+		ID:                   "ocid1.securitylist.oc1..standin",
+		State:                baremetal.ResourceAvailable,
+		IngressSecurityRules: []baremetal.IngressSecurityRule{https},
+	}}
+	clients, closeServer, err := newStandInClient(standIn.ServeHTTP, func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := resourcesMap()["oci_core_security_list_rule"]
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"security_list_id": "ocid1.securitylist.oc1..standin",
+		"direction":        "INGRESS",
+		"protocol":         "tcp",
+		"source":           "0.0.0.0/0",
+		"tcp_options":      []interface{}{map[string]interface{}{"min": 443, "max": 443}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(nil, terraform.NewResourceConfig(raw))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// A rule that is already in the list isn't adopted, since destroying
+	// this resource would remove it from under whoever added it.
+	state, err := r.Apply(nil, diff, clients)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "already has a duplicate rule")
+	}
+	assert.True(t, state == nil || state.ID == "")
+	assert.Equal(t, 0, standIn.updates)
+
+	// With the same rule in the list twice, destroying removes only one.
+	standIn.list.IngressSecurityRules = []baremetal.IngressSecurityRule{https, https}
+	d := r.Data(nil)
+	for k, v := range map[string]interface{}{
+		"security_list_id": "ocid1.securitylist.oc1..standin",
+		"direction":        "INGRESS",
+		"protocol":         "tcp",
+		"source":           "0.0.0.0/0",
+		"tcp_options":      []interface{}{map[string]interface{}{"min": 443, "max": 443}},
+	} {
+		if err := d.Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d.SetId((&SecurityListRuleResourceCrud{BaseCrud: crud.BaseCrud{D: d}}).ID())
+	_, err = r.Apply(d.State(), &terraform.InstanceDiff{Destroy: true}, clients)
+	assert.Nil(t, err)
+	assert.Equal(t, []baremetal.IngressSecurityRule{https}, standIn.list.IngressSecurityRules)
+}
+
+func TestSecurityListUpdateKeepsUnchangedRules(t *testing.T) {
+	ssh := baremetal.IngressSecurityRule{Source: "0.0.0.0/0", Protocol: "6", TCPOptions: &baremetal.TCPOptions{DestinationPortRange: &baremetal.PortRange{Min: 22, Max: 22}}}
+	https := baremetal.IngressSecurityRule{Source: "0.0.0.0/0", Protocol: "6", TCPOptions: &baremetal.TCPOptions{DestinationPortRange: &baremetal.PortRange{Min: 443, Max: 443}}}
+	standIn := &securityListStandIn{list: baremetal.SecurityList{
+		ID:                   "ocid1.securitylist.oc1..standin",
+		DisplayName:          "before",
+		State:                baremetal.ResourceAvailable,
+		IngressSecurityRules: []baremetal.IngressSecurityRule{ssh, https},
+	}}
+	clients, closeServer, err := newStandInClient(standIn.ServeHTTP, func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	// The list's own rules, without the one oci_core_security_list_rule
+	// added, as when its rules are in lifecycle.ignore_changes.
+	r := resourcesMap()["oci_core_security_list"]
+	d := r.Data(&terraform.InstanceState{ID: "ocid1.securitylist.oc1..standin"})
+	_, ingress := buildConfRuleLists(&baremetal.SecurityList{IngressSecurityRules: []baremetal.IngressSecurityRule{ssh}})
+	for k, v := range map[string]interface{}{
+		"compartment_id":         "ocid1.compartment.oc1..standin",
+		"vcn_id":                 "ocid1.vcn.oc1..standin",
+		"display_name":           "before",
+		"etag":                   "etag-0",
+		"ingress_security_rules": ingress,
+	} {
+		if err := d.Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"compartment_id": "ocid1.compartment.oc1..standin",
+		"vcn_id":         "ocid1.vcn.oc1..standin",
+		"display_name":   "after",
+		"ingress_security_rules": []interface{}{map[string]interface{}{
+			"source":      "0.0.0.0/0",
+			"protocol":    "6",
+			"tcp_options": []interface{}{map[string]interface{}{"min": 22, "max": 22}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(d.State(), terraform.NewResourceConfig(raw))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	_, err = r.Apply(d.State(), diff, clients)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, standIn.updates)
+	assert.Equal(t, []baremetal.IngressSecurityRule{ssh, https}, standIn.list.IngressSecurityRules)
+}
+
+func TestSecurityListRuleResource_lifecycle(t *testing.T) {
+	standIn := &securityListStandIn{list: baremetal.SecurityList{ID: "ocid1.securitylist.oc1..standin"}}
+	clients, closeServer, err := newStandInClient(standIn.ServeHTTP, func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	// The list ignores the rule added to it, applying again changes nothing,
+	// and the rule can be imported. Destroying both then succeeds.
+	resource.UnitTest(t, resource.TestCase{
+		Providers: standInProviders(clients),
+		Steps: []resource.TestStep{
+			{
+				Config: securityListWithRuleConfig("list", ""),
+			},
+			{
+				Config: securityListWithRuleConfig("list", securityListHTTPSRuleConfig),
+				Check: func(*terraform.State) error {
+					if len(standIn.list.IngressSecurityRules) != 2 {
+						return fmt.Errorf("Expected 2 ingress rules, got %v", standIn.list.IngressSecurityRules)
+					}
+					return nil
+				},
+			},
+			{
+				Config: securityListWithRuleConfig("list", securityListHTTPSRuleConfig),
+			},
+			{
+				ResourceName:      "oci_core_security_list_rule.t",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Imported as the protocol number the service reports,
+				// which is the same as the configured name.
+				ImportStateVerifyIgnore: []string{"protocol"},
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			if standIn.list.State != baremetal.ResourceTerminated {
+				return fmt.Errorf("Security list is %s", standIn.list.State)
+			}
+			return nil
+		},
+	})
+}
+
+func TestSecurityListRuleResource_interrupted(t *testing.T) {
+	standIn := &securityListStandIn{list: baremetal.SecurityList{
+		ID:    "ocid1.securitylist.oc1..standin",
+		State: baremetal.ResourceAvailable,
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var updates int32
+	clients, closeServer, err := newStandInClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			// Another writer always gets in first.
+			standIn.Lock()
+			standIn.etag++
+			standIn.Unlock()
+			if atomic.AddInt32(&updates, 1) == 2 {
+				cancel()
+			}
+		}
+		standIn.ServeHTTP(w, r)
+	}, func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+	clients.stopContext = ctx
+
+	r := resourcesMap()["oci_core_security_list_rule"]
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"security_list_id": "ocid1.securitylist.oc1..standin",
+		"direction":        "EGRESS",
+		"protocol":         "all",
+		"destination":      "0.0.0.0/0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(nil, terraform.NewResourceConfig(raw))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// Interrupting Terraform stops the retries, rather than writing to the
+	// list until the timeout.
+	start := time.Now()
+	_, err = r.Apply(nil, diff, clients)
+	assert.True(t, time.Since(start) < 5*time.Second)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Interrupted")
+	}
+	time.Sleep(time.Second)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&updates))
+	assert.Empty(t, standIn.list.EgressSecurityRules)
+}
+
+func TestSecurityListRuleResource_importBadID(t *testing.T) {
+	r := resourcesMap()["oci_core_security_list_rule"]
+	for _, id := range []string{
+		"ocid1.securitylist.oc1..standin",
+		"ocid1.securitylist.oc1..standin/SIDEWAYS/123",
+		"ocid1.securitylist.oc1..standin/INGRESS/abc",
+	} {
+		d := r.Data(&terraform.InstanceState{ID: id})
+		_, err := importSecurityListRule(d, nil)
+		assert.NotNil(t, err, id)
+	}
+}
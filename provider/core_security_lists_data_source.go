@@ -44,7 +44,10 @@ func SecurityListDatasource() *schema.Resource {
 }
 
 func readSecurityLists(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &SecurityListDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
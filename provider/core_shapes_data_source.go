@@ -55,7 +55,10 @@ func InstanceShapeDatasource() *schema.Resource {
 }
 
 func readInstanceShape(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &InstanceShapeDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
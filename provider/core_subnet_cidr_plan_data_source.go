@@ -0,0 +1,147 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+
+	"github.com/oracle/terraform-provider-oci/crud"
+)
+
+// maxSubnetPrefixLength is the longest prefix, i.e. the smallest subnet, a
+// subnet can have.
+const maxSubnetPrefixLength = 30
+
+// SubnetCIDRPlanDatasource carves subnets of the given sizes out of a VCN's
+// CIDR block, without calling the API, e.g. to lay out a subnet per
+// availability domain.
+func SubnetCIDRPlanDatasource() *schema.Resource {
+	return &schema.Resource{
+		Read: readSubnetCIDRPlan,
+		Schema: map[string]*schema.Schema{
+			"cidr_block": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateCIDRBlock,
+			},
+			"prefix_lengths": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"reserved_cidr_blocks": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateCIDRBlock,
+				},
+			},
+			// Computed
+			"subnet_cidr_blocks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func readSubnetCIDRPlan(d *schema.ResourceData, m interface{}) (e error) {
+	sync := &SubnetCIDRPlanDatasourceCrud{}
+	sync.D = d
+	return crud.ReadResource(sync)
+}
+
+type SubnetCIDRPlanDatasourceCrud struct {
+	crud.BaseCrud
+	CIDRBlocks []string
+}
+
+func (s *SubnetCIDRPlanDatasourceCrud) Get() (e error) {
+	var prefixLengths []int
+	for _, val := range s.D.Get("prefix_lengths").([]interface{}) {
+		prefixLengths = append(prefixLengths, val.(int))
+	}
+	var reserved []string
+	for _, val := range s.D.Get("reserved_cidr_blocks").([]interface{}) {
+		reserved = append(reserved, val.(string))
+	}
+	s.CIDRBlocks, e = planSubnetCIDRBlocks(s.D.Get("cidr_block").(string), prefixLengths, reserved)
+	return
+}
+
+func (s *SubnetCIDRPlanDatasourceCrud) SetData() {
+	s.D.SetId(time.Now().UTC().String())
+	s.D.Set("subnet_cidr_blocks", s.CIDRBlocks)
+}
+
+// planSubnetCIDRBlocks returns a CIDR block within vcnCIDRBlock for each of
+// prefixLengths, in the same order, that overlaps neither the others nor
+// reserved. Larger subnets are placed first, each at the lowest address it
+// fits at, so that smaller ones fill the gaps left between them.
+func planSubnetCIDRBlocks(vcnCIDRBlock string, prefixLengths []int, reserved []string) ([]string, error) {
+	vcn, err := parseCIDRBlock(vcnCIDRBlock)
+	if err != nil {
+		return nil, err
+	}
+	vcnOnes, _ := vcn.Mask.Size()
+
+	var taken []*net.IPNet
+	for _, cidrBlock := range reserved {
+		network, err := parseCIDRBlock(cidrBlock)
+		if err != nil {
+			return nil, err
+		}
+		taken = append(taken, network)
+	}
+
+	order := make([]int, len(prefixLengths))
+	for i, prefixLength := range prefixLengths {
+		if prefixLength < vcnOnes || prefixLength > maxSubnetPrefixLength {
+			return nil, fmt.Errorf("Prefix length %d is not between %d, that of %s, and %d", prefixLength, vcnOnes, vcnCIDRBlock, maxSubnetPrefixLength)
+		}
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return prefixLengths[order[i]] < prefixLengths[order[j]]
+	})
+
+	planned := make([]string, len(prefixLengths))
+	start := uint64(ipv4ToUint32(vcn.IP))
+	end := start + 1<<uint(32-vcnOnes)
+	for _, i := range order {
+		size := uint64(1) << uint(32-prefixLengths[i])
+		var subnet *net.IPNet
+		for addr := start; addr < end && subnet == nil; {
+			candidate := &net.IPNet{IP: uint32ToIPv4(uint32(addr)), Mask: net.CIDRMask(prefixLengths[i], 32)}
+			subnet = candidate
+			for _, network := range taken {
+				if cidrOverlaps(candidate, network) {
+					// Skip to the first aligned address past network.
+					ones, _ := network.Mask.Size()
+					networkEnd := uint64(ipv4ToUint32(network.IP)) + 1<<uint(32-ones)
+					addr = (networkEnd + size - 1) / size * size
+					subnet = nil
+					break
+				}
+			}
+		}
+		if subnet == nil {
+			return nil, fmt.Errorf("There is no room left in %s for a /%d subnet", vcnCIDRBlock, prefixLengths[i])
+		}
+		taken = append(taken, subnet)
+		planned[i] = subnet.String()
+	}
+	return planned, nil
+}
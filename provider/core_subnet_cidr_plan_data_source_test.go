@@ -0,0 +1,73 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanSubnetCIDRBlocks(t *testing.T) {
+	// Subnets are in the order of their prefix lengths, and larger ones are
+	// placed first, so the /26s fill the rest of the /24 the /25 is in.
+	planned, err := planSubnetCIDRBlocks("10.0.0.0/16", []int{26, 24, 25, 26}, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"10.0.1.128/26", "10.0.0.0/24", "10.0.1.0/25", "10.0.1.192/26"}, planned)
+	}
+
+	// Reserved blocks, e.g. existing subnets, are left out.
+	planned, err = planSubnetCIDRBlocks("10.0.0.0/16", []int{24, 24}, []string{"10.0.0.0/24", "10.0.2.0/23"})
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"10.0.1.0/24", "10.0.4.0/24"}, planned)
+	}
+
+	// A VCN can be filled up exactly.
+	planned, err = planSubnetCIDRBlocks("172.16.0.0/28", []int{30, 29, 30}, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"172.16.0.8/30", "172.16.0.0/29", "172.16.0.12/30"}, planned)
+	}
+
+	_, err = planSubnetCIDRBlocks("172.16.0.0/28", []int{29, 29, 30}, nil)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "no room left in 172.16.0.0/28 for a /30 subnet")
+	}
+	_, err = planSubnetCIDRBlocks("10.0.0.0/16", []int{15}, nil)
+	assert.NotNil(t, err)
+	_, err = planSubnetCIDRBlocks("10.0.0.0/16", []int{31}, nil)
+	assert.NotNil(t, err)
+	_, err = planSubnetCIDRBlocks("10.0.0.1/16", []int{24}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestSubnetCIDRPlanDatasource(t *testing.T) {
+	clients, closeServer, err := newStandInClient(http.NotFound, func(d *schema.ResourceData) {})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := dataSourcesMap()["oci_core_subnet_cidr_plan"]
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"cidr_block":           "10.0.0.0/16",
+		"prefix_lengths":       []interface{}{24, 24, 24},
+		"reserved_cidr_blocks": []interface{}{"10.0.0.0/24"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(nil, terraform.NewResourceConfig(raw))
+	if !assert.Nil(t, err) {
+		return
+	}
+	state, err := r.ReadDataApply(diff, clients)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "3", state.Attributes["subnet_cidr_blocks.#"])
+		assert.Equal(t, "10.0.1.0/24", state.Attributes["subnet_cidr_blocks.0"])
+		assert.Equal(t, "10.0.3.0/24", state.Attributes["subnet_cidr_blocks.2"])
+	}
+}
@@ -3,9 +3,12 @@
 package provider
 
 import (
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
 	"github.com/oracle/bmcs-go-sdk"
 
 	"github.com/oracle/terraform-provider-oci/crud"
@@ -28,9 +31,10 @@ func SubnetResource() *schema.Resource {
 				ForceNew: true,
 			},
 			"cidr_block": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateCIDRBlock,
 			},
 			"compartment_id": {
 				Type:     schema.TypeString,
@@ -42,6 +46,7 @@ func SubnetResource() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			"etag": crud.ETagSchema(),
 			"route_table_id": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -104,22 +109,77 @@ func SubnetResource() *schema.Resource {
 	}
 }
 
+// customizeSubnetDiff rejects a subnet whose cidr_block is outside of its
+// VCN's, when both are known at plan time, so that the mistake is caught
+// before anything else is applied. VCNs that can't be read are left for the
+// apply to report.
+func customizeSubnetDiff(s *terraform.InstanceState, c *terraform.ResourceConfig, diff *terraform.InstanceDiff, meta interface{}) error {
+	attr, ok := diff.Attributes["cidr_block"]
+	if !ok || attr.NewComputed || attr.NewRemoved || meta == nil {
+		return nil
+	}
+	subnetCIDR, err := parseCIDRBlock(attr.New)
+	if err != nil {
+		// The validator has reported it already.
+		return nil
+	}
+	vcnID, ok := c.Get("vcn_id")
+	if !ok || c.IsComputed("vcn_id") {
+		return nil
+	}
+
+	var region string
+	if val, ok := c.Get("region"); ok {
+		region, _ = val.(string)
+	} else if s != nil {
+		region = s.Attributes["region"]
+	}
+	clients, err := meta.(*OracleClients).ForRegion(region)
+	if err != nil {
+		return nil
+	}
+	vcn, err := clients.clientWithoutNotFoundRetries.GetVirtualNetwork(vcnID.(string))
+	if err != nil {
+		log.Printf("[DEBUG] Not checking cidr_block %s against VCN %s: %v", attr.New, vcnID, err)
+		return nil
+	}
+	vcnCIDR, err := parseCIDRBlock(vcn.CidrBlock)
+	if err != nil {
+		return nil
+	}
+	if !cidrContains(vcnCIDR, subnetCIDR) {
+		return fmt.Errorf("cidr_block %s of the subnet is not within %s, the CIDR block of VCN %s", attr.New, vcn.CidrBlock, vcn.ID)
+	}
+	return nil
+}
+
 func createSubnet(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &SubnetResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.CreateResource(d, sync)
 }
 
 func readSubnet(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &SubnetResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.ReadResource(sync)
 }
 
 func updateSubnet(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &SubnetResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -127,9 +187,13 @@ func updateSubnet(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteSubnet(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &SubnetResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).clientWithoutNotFoundRetries
+	sync.Client = client.clientWithoutNotFoundRetries
 	return crud.DeleteResource(d, sync)
 }
 
@@ -222,6 +286,7 @@ func (s *SubnetResourceCrud) Get() (e error) {
 
 func (s *SubnetResourceCrud) Update() (e error) {
 	opts := &baremetal.IfMatchDisplayNameOptions{}
+	opts.IfMatch = s.IfMatch()
 
 	displayName, ok := s.D.GetOk("display_name")
 	if ok {
@@ -251,7 +316,7 @@ func (s *SubnetResourceCrud) SetData() {
 }
 
 func (s *SubnetResourceCrud) Delete() (e error) {
-	return s.Client.DeleteSubnet(s.D.Id(), nil)
+	return s.Client.DeleteSubnet(s.D.Id(), s.IfMatchOptions())
 }
 
 // makeSetFromStrings encodes an []string into a
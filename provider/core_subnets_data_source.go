@@ -114,7 +114,10 @@ func resourceCoreSubnets() *schema.Resource {
 }
 
 func readSubnets(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &SubnetDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
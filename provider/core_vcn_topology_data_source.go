@@ -0,0 +1,465 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/oracle/bmcs-go-sdk"
+
+	"github.com/oracle/terraform-provider-oci/options"
+
+	"github.com/oracle/terraform-provider-oci/crud"
+)
+
+// VCNTopologyDatasource reads a VCN together with its subnets, route tables,
+// security lists, DHCP options, internet gateways and DRG attachments, and
+// renders how they refer to each other as a Graphviz graph.
+func VCNTopologyDatasource() *schema.Resource {
+	return &schema.Resource{
+		Read: readVCNTopology,
+		Schema: map[string]*schema.Schema{
+			"compartment_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"vcn_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			// Computed
+			"cidr_block": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"dhcp_options": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     vcnTopologyEntitySchema(nil),
+			},
+			"display_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"dot": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"drg_attachments": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: vcnTopologyEntitySchema(map[string]*schema.Schema{
+					"drg_id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				}),
+			},
+			"internet_gateways": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: vcnTopologyEntitySchema(map[string]*schema.Schema{
+					"enabled": {
+						Type:     schema.TypeBool,
+						Computed: true,
+					},
+				}),
+			},
+			"route_tables": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: vcnTopologyEntitySchema(map[string]*schema.Schema{
+					"route_rules": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"cidr_block": {
+									Type:     schema.TypeString,
+									Computed: true,
+								},
+								"network_entity_id": {
+									Type:     schema.TypeString,
+									Computed: true,
+								},
+							},
+						},
+					},
+				}),
+			},
+			"security_lists": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     vcnTopologyEntitySchema(nil),
+			},
+			"subnets": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: vcnTopologyEntitySchema(map[string]*schema.Schema{
+					"availability_domain": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"cidr_block": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"dhcp_options_id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"route_table_id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"security_list_ids": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+				}),
+			},
+		},
+	}
+}
+
+// vcnTopologyEntitySchema is the id, display_name and state every entity of
+// the topology has, plus fields.
+func vcnTopologyEntitySchema(fields map[string]*schema.Schema) *schema.Resource {
+	s := map[string]*schema.Schema{
+		"display_name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"state": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+	for k, v := range fields {
+		s[k] = v
+	}
+	return &schema.Resource{Schema: s}
+}
+
+func readVCNTopology(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
+	reader := &VCNTopologyDatasourceCrud{}
+	reader.D = d
+	reader.Client = client.client
+	return crud.ReadResource(reader)
+}
+
+type VCNTopologyDatasourceCrud struct {
+	crud.BaseCrud
+	VCN              *baremetal.VirtualNetwork
+	Subnets          []baremetal.Subnet
+	RouteTables      []baremetal.RouteTable
+	SecurityLists    []baremetal.SecurityList
+	DHCPOptions      []baremetal.DHCPOptions
+	InternetGateways []baremetal.InternetGateway
+	DrgAttachments   []baremetal.DrgAttachment
+}
+
+// Get reads the VCN, and then lists its entities concurrently. Entities are
+// listed in compartment_id, which defaults to the VCN's compartment.
+func (s *VCNTopologyDatasourceCrud) Get() (e error) {
+	if s.VCN, e = s.Client.GetVirtualNetwork(s.D.Get("vcn_id").(string)); e != nil {
+		return
+	}
+	compartmentID := s.VCN.CompartmentID
+	if val, ok := s.D.GetOk("compartment_id"); ok {
+		compartmentID = val.(string)
+	}
+	vcnID := s.VCN.ID
+
+	listers := []func() error{
+		func() error {
+			return listPages(func(opts *baremetal.ListOptions) (string, error) {
+				list, err := s.Client.ListSubnets(compartmentID, vcnID, opts)
+				if err != nil {
+					return "", err
+				}
+				s.Subnets = append(s.Subnets, list.Subnets...)
+				return list.NextPage, nil
+			})
+		},
+		func() error {
+			return listPages(func(opts *baremetal.ListOptions) (string, error) {
+				list, err := s.Client.ListRouteTables(compartmentID, vcnID, opts)
+				if err != nil {
+					return "", err
+				}
+				s.RouteTables = append(s.RouteTables, list.RouteTables...)
+				return list.NextPage, nil
+			})
+		},
+		func() error {
+			return listPages(func(opts *baremetal.ListOptions) (string, error) {
+				list, err := s.Client.ListSecurityLists(compartmentID, vcnID, opts)
+				if err != nil {
+					return "", err
+				}
+				s.SecurityLists = append(s.SecurityLists, list.SecurityLists...)
+				return list.NextPage, nil
+			})
+		},
+		func() error {
+			return listPages(func(opts *baremetal.ListOptions) (string, error) {
+				list, err := s.Client.ListDHCPOptions(compartmentID, vcnID, opts)
+				if err != nil {
+					return "", err
+				}
+				s.DHCPOptions = append(s.DHCPOptions, list.DHCPOptions...)
+				return list.NextPage, nil
+			})
+		},
+		func() error {
+			return listPages(func(opts *baremetal.ListOptions) (string, error) {
+				list, err := s.Client.ListInternetGateways(compartmentID, vcnID, opts)
+				if err != nil {
+					return "", err
+				}
+				s.InternetGateways = append(s.InternetGateways, list.Gateways...)
+				return list.NextPage, nil
+			})
+		},
+		func() error {
+			return listPages(func(opts *baremetal.ListOptions) (string, error) {
+				list, err := s.Client.ListDrgAttachments(compartmentID, &baremetal.ListDrgAttachmentsOptions{ListOptions: *opts, VcnID: vcnID})
+				if err != nil {
+					return "", err
+				}
+				s.DrgAttachments = append(s.DrgAttachments, list.DrgAttachments...)
+				return list.NextPage, nil
+			})
+		},
+	}
+
+	errs := make([]error, len(listers))
+	var wg sync.WaitGroup
+	for i, list := range listers {
+		wg.Add(1)
+		go func(i int, list func() error) {
+			defer wg.Done()
+			errs[i] = list()
+		}(i, list)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return
+}
+
+// listPages calls list with each page's options, from the first page until
+// list returns no next page.
+func listPages(list func(opts *baremetal.ListOptions) (nextPage string, e error)) error {
+	opts := &baremetal.ListOptions{}
+	for {
+		nextPage, err := list(opts)
+		if err != nil {
+			return err
+		}
+		if hasNextPage := options.SetNextPageOption(nextPage, &opts.PageListOptions); !hasNextPage {
+			return nil
+		}
+	}
+}
+
+func (s *VCNTopologyDatasourceCrud) SetData() {
+	if s.VCN == nil {
+		return
+	}
+
+	s.D.SetId(time.Now().UTC().String())
+	s.D.Set("cidr_block", s.VCN.CidrBlock)
+	s.D.Set("display_name", s.VCN.DisplayName)
+	if _, ok := s.D.GetOk("compartment_id"); !ok {
+		s.D.Set("compartment_id", s.VCN.CompartmentID)
+	}
+
+	subnets := []map[string]interface{}{}
+	for _, v := range s.Subnets {
+		subnets = append(subnets, map[string]interface{}{
+			"availability_domain": v.AvailabilityDomain,
+			"cidr_block":          v.CIDRBlock,
+			"dhcp_options_id":     v.DHCPOptionsID,
+			"display_name":        v.DisplayName,
+			"id":                  v.ID,
+			"route_table_id":      v.RouteTableID,
+			"security_list_ids":   v.SecurityListIDs,
+			"state":               v.State,
+		})
+	}
+	s.D.Set("subnets", subnets)
+
+	routeTables := []map[string]interface{}{}
+	for _, v := range s.RouteTables {
+		rules := []map[string]interface{}{}
+		for _, rule := range v.RouteRules {
+			rules = append(rules, map[string]interface{}{
+				"cidr_block":        rule.CidrBlock,
+				"network_entity_id": rule.NetworkEntityID,
+			})
+		}
+		routeTables = append(routeTables, map[string]interface{}{
+			"display_name": v.DisplayName,
+			"id":           v.ID,
+			"route_rules":  rules,
+			"state":        v.State,
+		})
+	}
+	s.D.Set("route_tables", routeTables)
+
+	securityLists := []map[string]interface{}{}
+	for _, v := range s.SecurityLists {
+		securityLists = append(securityLists, map[string]interface{}{
+			"display_name": v.DisplayName,
+			"id":           v.ID,
+			"state":        v.State,
+		})
+	}
+	s.D.Set("security_lists", securityLists)
+
+	dhcpOptions := []map[string]interface{}{}
+	for _, v := range s.DHCPOptions {
+		dhcpOptions = append(dhcpOptions, map[string]interface{}{
+			"display_name": v.DisplayName,
+			"id":           v.ID,
+			"state":        v.State,
+		})
+	}
+	s.D.Set("dhcp_options", dhcpOptions)
+
+	internetGateways := []map[string]interface{}{}
+	for _, v := range s.InternetGateways {
+		internetGateways = append(internetGateways, map[string]interface{}{
+			"display_name": v.DisplayName,
+			"enabled":      v.IsEnabled,
+			"id":           v.ID,
+			"state":        v.State,
+		})
+	}
+	s.D.Set("internet_gateways", internetGateways)
+
+	drgAttachments := []map[string]interface{}{}
+	for _, v := range s.DrgAttachments {
+		drgAttachments = append(drgAttachments, map[string]interface{}{
+			"display_name": v.DisplayName,
+			"drg_id":       v.DrgID,
+			"id":           v.ID,
+			"state":        v.State,
+		})
+	}
+	s.D.Set("drg_attachments", drgAttachments)
+
+	s.D.Set("dot", s.dot())
+}
+
+// dot renders the topology as a Graphviz digraph. The VCN points at its
+// subnets and gateways, subnets at the route table, security lists and DHCP
+// options they use, and route tables at the targets of their rules. Nodes
+// are identified by OCID and labelled with display names and CIDR blocks.
+func (s *VCNTopologyDatasourceCrud) dot() string {
+	var buf bytes.Buffer
+	nodes := map[string]bool{}
+	node := func(id, shape string, label ...string) {
+		nodes[id] = true
+		fmt.Fprintf(&buf, "  %s [shape=%s, label=%s];\n", dotQuote(id), shape, dotQuote(strings.Join(label, "\n")))
+	}
+	edge := func(from, to string, attrs string) {
+		if to == "" {
+			return
+		}
+		fmt.Fprintf(&buf, "  %s -> %s", dotQuote(from), dotQuote(to))
+		if attrs != "" {
+			fmt.Fprintf(&buf, " [%s]", attrs)
+		}
+		buf.WriteString(";\n")
+	}
+
+	fmt.Fprintf(&buf, "digraph %s {\n  rankdir=LR;\n", dotQuote(s.VCN.DisplayName))
+	node(s.VCN.ID, "box3d", "VCN "+s.VCN.DisplayName, s.VCN.CidrBlock)
+	for _, v := range s.Subnets {
+		node(v.ID, "box", "Subnet "+v.DisplayName, v.CIDRBlock, v.AvailabilityDomain)
+	}
+	for _, v := range s.RouteTables {
+		node(v.ID, "note", "Route table "+v.DisplayName)
+	}
+	for _, v := range s.SecurityLists {
+		node(v.ID, "note", "Security list "+v.DisplayName)
+	}
+	for _, v := range s.DHCPOptions {
+		node(v.ID, "note", "DHCP options "+v.DisplayName)
+	}
+	for _, v := range s.InternetGateways {
+		node(v.ID, "doublecircle", "Internet gateway "+v.DisplayName)
+	}
+	for _, v := range s.DrgAttachments {
+		if !nodes[v.DrgID] {
+			node(v.DrgID, "doublecircle", "DRG")
+		}
+	}
+	// Route targets outside of the VCN, e.g. private IPs, are labelled with
+	// the type of their OCID.
+	for _, v := range s.RouteTables {
+		for _, rule := range v.RouteRules {
+			if !nodes[rule.NetworkEntityID] {
+				node(rule.NetworkEntityID, "ellipse", ocidResourceType(rule.NetworkEntityID))
+			}
+		}
+	}
+
+	for _, v := range s.Subnets {
+		edge(s.VCN.ID, v.ID, "")
+		edge(v.ID, v.RouteTableID, `label="routes"`)
+		for _, id := range v.SecurityListIDs {
+			edge(v.ID, id, "style=dashed")
+		}
+		edge(v.ID, v.DHCPOptionsID, "style=dotted")
+	}
+	for _, v := range s.InternetGateways {
+		edge(s.VCN.ID, v.ID, "")
+	}
+	for _, v := range s.DrgAttachments {
+		edge(s.VCN.ID, v.DrgID, dotQuoteAttr("label", "attachment "+v.DisplayName))
+	}
+	for _, v := range s.RouteTables {
+		for _, rule := range v.RouteRules {
+			edge(v.ID, rule.NetworkEntityID, dotQuoteAttr("label", rule.CidrBlock))
+		}
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// dotQuote quotes s as a DOT ID, in which a newline starts a new line of a
+// label.
+func dotQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	s = strings.Replace(s, "\n", `\n`, -1)
+	return `"` + s + `"`
+}
+
+func dotQuoteAttr(name, value string) string {
+	return name + "=" + dotQuote(value)
+}
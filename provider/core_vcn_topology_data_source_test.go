@@ -0,0 +1,129 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// vcnTopologyStandIn serves a VCN with two subnets, listed on two pages, and
+// the entities they use. It holds list requests until all six are in flight,
+// so that listing them one after the other times out.
+func vcnTopologyStandIn(t *testing.T) http.HandlerFunc {
+	var mu sync.Mutex
+	listing := 0
+	allListing := make(chan struct{})
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		path := r.URL.Path
+		if strings.HasSuffix(path, "/vcns/ocid1.vcn.oc1..standin") {
+			w.Write([]byte(`{"id":"ocid1.vcn.oc1..standin","compartmentId":"ocid1.compartment.oc1..standin","displayName":"prod \"east\"","cidrBlock":"10.0.0.0/16","lifecycleState":"AVAILABLE"}`))
+			return
+		}
+		if r.URL.Query().Get("compartmentId") != "ocid1.compartment.oc1..standin" {
+			t.Errorf("%s is not listed in the VCN's compartment", path)
+		}
+
+		if r.URL.Query().Get("page") == "" {
+			mu.Lock()
+			if listing++; listing == 6 {
+				close(allListing)
+			}
+			mu.Unlock()
+			select {
+			case <-allListing:
+			case <-time.After(5 * time.Second):
+				t.Errorf("%s is listed before the other entities", path)
+			}
+		}
+
+		switch {
+		case strings.HasSuffix(path, "/subnets") && r.URL.Query().Get("page") == "":
+			w.Header().Set("opc-next-page", "2")
+			w.Write([]byte(`[{"id":"ocid1.subnet.oc1..a","displayName":"a","cidrBlock":"10.0.1.0/24","availabilityDomain":"ad-1","routeTableId":"ocid1.routetable.oc1..standin","securityListIds":["ocid1.securitylist.oc1..standin"],"dhcpOptionsId":"ocid1.dhcpoptions.oc1..standin","lifecycleState":"AVAILABLE"}]`))
+		case strings.HasSuffix(path, "/subnets"):
+			w.Write([]byte(`[{"id":"ocid1.subnet.oc1..b","displayName":"b","cidrBlock":"10.0.2.0/24","availabilityDomain":"ad-2","routeTableId":"ocid1.routetable.oc1..standin","securityListIds":[],"dhcpOptionsId":"ocid1.dhcpoptions.oc1..standin","lifecycleState":"AVAILABLE"}]`))
+		case strings.HasSuffix(path, "/routeTables"):
+			w.Write([]byte(`[{"id":"ocid1.routetable.oc1..standin","displayName":"public","routeRules":[{"cidrBlock":"0.0.0.0/0","networkEntityId":"ocid1.internetgateway.oc1..standin"},{"cidrBlock":"192.168.0.0/16","networkEntityId":"ocid1.privateip.oc1..standin"}],"lifecycleState":"AVAILABLE"}]`))
+		case strings.HasSuffix(path, "/securityLists"):
+			w.Write([]byte(`[{"id":"ocid1.securitylist.oc1..standin","displayName":"web","lifecycleState":"AVAILABLE"}]`))
+		case strings.HasSuffix(path, "/dhcps"):
+			w.Write([]byte(`[{"id":"ocid1.dhcpoptions.oc1..standin","displayName":"dns","lifecycleState":"AVAILABLE"}]`))
+		case strings.HasSuffix(path, "/internetGateways"):
+			w.Write([]byte(`[{"id":"ocid1.internetgateway.oc1..standin","displayName":"igw","isEnabled":true,"lifecycleState":"AVAILABLE"}]`))
+		case strings.HasSuffix(path, "/drgAttachments"):
+			if r.URL.Query().Get("vcnId") != "ocid1.vcn.oc1..standin" {
+				t.Errorf("DRG attachments are not listed for the VCN")
+			}
+			w.Write([]byte(`[{"id":"ocid1.drgattachment.oc1..standin","displayName":"onprem","drgId":"ocid1.drg.oc1..standin","lifecycleState":"ATTACHED"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":"NotAuthorizedOrNotFound","message":"not found"}`))
+		}
+	}
+}
+
+func TestVCNTopologyDatasource(t *testing.T) {
+	clients, closeServer, err := newStandInClient(vcnTopologyStandIn(t), func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	r := dataSourcesMap()["oci_core_vcn_topology"]
+	raw, err := config.NewRawConfig(map[string]interface{}{"vcn_id": "ocid1.vcn.oc1..standin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := r.Diff(nil, terraform.NewResourceConfig(raw))
+	if !assert.Nil(t, err) {
+		return
+	}
+	state, err := r.ReadDataApply(diff, clients)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	attrs := state.Attributes
+	assert.Equal(t, "ocid1.compartment.oc1..standin", attrs["compartment_id"])
+	assert.Equal(t, "10.0.0.0/16", attrs["cidr_block"])
+	assert.Equal(t, "2", attrs["subnets.#"])
+	assert.Equal(t, "ocid1.subnet.oc1..b", attrs["subnets.1.id"])
+	assert.Equal(t, "ocid1.securitylist.oc1..standin", attrs["subnets.0.security_list_ids.0"])
+	assert.Equal(t, "2", attrs["route_tables.0.route_rules.#"])
+	assert.Equal(t, "ocid1.privateip.oc1..standin", attrs["route_tables.0.route_rules.1.network_entity_id"])
+	assert.Equal(t, "1", attrs["security_lists.#"])
+	assert.Equal(t, "1", attrs["dhcp_options.#"])
+	assert.Equal(t, "true", attrs["internet_gateways.0.enabled"])
+	assert.Equal(t, "ocid1.drg.oc1..standin", attrs["drg_attachments.0.drg_id"])
+
+	dot := attrs["dot"]
+	assert.True(t, strings.HasPrefix(dot, `digraph "prod \"east\"" {`), dot)
+	for _, line := range []string{
+		`"ocid1.vcn.oc1..standin" [shape=box3d, label="VCN prod \"east\"\n10.0.0.0/16"];`,
+		`"ocid1.subnet.oc1..a" [shape=box, label="Subnet a\n10.0.1.0/24\nad-1"];`,
+		`"ocid1.privateip.oc1..standin" [shape=ellipse, label="privateip"];`,
+		`"ocid1.drg.oc1..standin" [shape=doublecircle, label="DRG"];`,
+		`"ocid1.vcn.oc1..standin" -> "ocid1.subnet.oc1..b";`,
+		`"ocid1.subnet.oc1..a" -> "ocid1.routetable.oc1..standin" [label="routes"];`,
+		`"ocid1.subnet.oc1..a" -> "ocid1.securitylist.oc1..standin" [style=dashed];`,
+		`"ocid1.subnet.oc1..b" -> "ocid1.dhcpoptions.oc1..standin" [style=dotted];`,
+		`"ocid1.vcn.oc1..standin" -> "ocid1.drg.oc1..standin" [label="attachment onprem"];`,
+		`"ocid1.routetable.oc1..standin" -> "ocid1.internetgateway.oc1..standin" [label="0.0.0.0/0"];`,
+	} {
+		assert.Contains(t, dot, "  "+line+"\n")
+	}
+	// The internet gateway is declared as such, not as a bare route target.
+	assert.Equal(t, 1, strings.Count(dot, `"ocid1.internetgateway.oc1..standin" [shape=`))
+}
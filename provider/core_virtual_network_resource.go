@@ -21,9 +21,10 @@ func VirtualNetworkResource() *schema.Resource {
 		Delete:   deleteVirtualNetwork,
 		Schema: map[string]*schema.Schema{
 			"cidr_block": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateCIDRBlock,
 			},
 			"compartment_id": {
 				Type:     schema.TypeString,
@@ -53,6 +54,7 @@ func VirtualNetworkResource() *schema.Resource {
 				ForceNew:         true,
 				DiffSuppressFunc: crud.EqualIgnoreCaseSuppressDiff,
 			},
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -70,7 +72,10 @@ func VirtualNetworkResource() *schema.Resource {
 }
 
 func createVirtualNetwork(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VirtualNetworkResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -78,7 +83,10 @@ func createVirtualNetwork(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readVirtualNetwork(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VirtualNetworkResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -86,7 +94,10 @@ func readVirtualNetwork(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func updateVirtualNetwork(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VirtualNetworkResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -94,7 +105,10 @@ func updateVirtualNetwork(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteVirtualNetwork(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VirtualNetworkResourceCrud{}
 	sync.D = d
 	sync.Client = client.clientWithoutNotFoundRetries
@@ -156,6 +170,7 @@ func (s *VirtualNetworkResourceCrud) Get() (e error) {
 
 func (s *VirtualNetworkResourceCrud) Update() (e error) {
 	opts := &baremetal.IfMatchDisplayNameOptions{}
+	opts.IfMatch = s.IfMatch()
 
 	displayName, ok := s.D.GetOk("display_name")
 	if ok {
@@ -179,5 +194,5 @@ func (s *VirtualNetworkResourceCrud) SetData() {
 }
 
 func (s *VirtualNetworkResourceCrud) Delete() (e error) {
-	return s.Client.DeleteVirtualNetwork(s.D.Id(), nil)
+	return s.Client.DeleteVirtualNetwork(s.D.Id(), s.IfMatchOptions())
 }
@@ -40,7 +40,10 @@ func VirtualNetworkDatasource() *schema.Resource {
 }
 
 func readVirtualNetworks(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VirtualNetworkDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -43,6 +43,7 @@ func VnicAttachmentResource() *schema.Resource {
 				Optional: true,
 				ForceNew: true,
 			},
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -77,30 +78,46 @@ func VnicAttachmentResource() *schema.Resource {
 }
 
 func createVnicAttachment(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VnicAttachmentResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.CreateResource(d, sync)
 }
 
 func readVnicAttachment(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VnicAttachmentResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.ReadResource(sync)
 }
 
 func updateVnicAttachment(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VnicAttachmentResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.UpdateResource(sync.D, sync)
 }
 
 func deleteVnicAttachment(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VnicAttachmentResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).clientWithoutNotFoundRetries
+	sync.Client = client.clientWithoutNotFoundRetries
 	return crud.DeleteResource(d, sync)
 }
 
@@ -187,5 +204,5 @@ func (s *VnicAttachmentResourceCrud) SetData() {
 }
 
 func (s *VnicAttachmentResourceCrud) Delete() (e error) {
-	return s.Client.DetachVnic(s.D.Id(), nil)
+	return s.Client.DetachVnic(s.D.Id(), s.IfMatchOptions())
 }
@@ -95,7 +95,10 @@ func resourceVnicAttachment() *schema.Resource {
 }
 
 func readVnicAttachments(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &VnicAttachmentDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
@@ -74,7 +74,10 @@ func VnicDatasource() *schema.Resource {
 }
 
 func readVnic(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VnicDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
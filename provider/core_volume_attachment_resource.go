@@ -32,6 +32,7 @@ func VolumeAttachmentResource() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			"etag": crud.ETagSchema(),
 			"instance_id": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -91,7 +92,10 @@ func VolumeAttachmentResource() *schema.Resource {
 }
 
 func createVolumeAttachment(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VolumeAttachmentResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -99,7 +103,10 @@ func createVolumeAttachment(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readVolumeAttachment(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VolumeAttachmentResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -107,7 +114,10 @@ func readVolumeAttachment(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteVolumeAttachment(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VolumeAttachmentResourceCrud{}
 	sync.D = d
 	sync.Client = client.clientWithoutNotFoundRetries
@@ -178,5 +188,5 @@ func (s *VolumeAttachmentResourceCrud) SetData() {
 }
 
 func (s *VolumeAttachmentResourceCrud) Delete() (e error) {
-	return s.Client.DetachVolume(s.D.Id(), nil)
+	return s.Client.DetachVolume(s.D.Id(), s.IfMatchOptions())
 }
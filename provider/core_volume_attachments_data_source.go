@@ -52,7 +52,10 @@ func VolumeAttachmentDatasource() *schema.Resource {
 }
 
 func readVolumeAttachments(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VolumeAttachmentDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
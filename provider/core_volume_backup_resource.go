@@ -29,6 +29,7 @@ func VolumeBackupResource() *schema.Resource {
 				Computed: true,
 				Optional: true,
 			},
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -73,7 +74,10 @@ func VolumeBackupResource() *schema.Resource {
 }
 
 func createVolumeBackup(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VolumeBackupResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -81,7 +85,10 @@ func createVolumeBackup(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readVolumeBackup(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VolumeBackupResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -89,7 +96,10 @@ func readVolumeBackup(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func updateVolumeBackup(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VolumeBackupResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -97,7 +107,10 @@ func updateVolumeBackup(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteVolumeBackup(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VolumeBackupResourceCrud{}
 	sync.D = d
 	sync.Client = client.clientWithoutNotFoundRetries
@@ -158,6 +171,7 @@ func (s *VolumeBackupResourceCrud) Get() (e error) {
 
 func (s *VolumeBackupResourceCrud) Update() (e error) {
 	opts := &baremetal.IfMatchDisplayNameOptions{}
+	opts.IfMatch = s.IfMatch()
 	displayName, ok := s.D.GetOk("display_name")
 	if ok {
 		opts.DisplayName = displayName.(string)
@@ -184,5 +198,5 @@ func (s *VolumeBackupResourceCrud) SetData() {
 }
 
 func (s *VolumeBackupResourceCrud) Delete() (e error) {
-	return s.Client.DeleteVolumeBackup(s.D.Id(), nil)
+	return s.Client.DeleteVolumeBackup(s.D.Id(), s.IfMatchOptions())
 }
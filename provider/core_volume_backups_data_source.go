@@ -44,7 +44,10 @@ func VolumeBackupDatasource() *schema.Resource {
 }
 
 func readVolumeBackups(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VolumeBackupDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
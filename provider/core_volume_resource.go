@@ -32,6 +32,7 @@ func VolumeResource() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			"etag": crud.ETagSchema(),
 			"size_in_mbs": {
 				Type:       schema.TypeInt,
 				Optional:   true,
@@ -93,7 +94,10 @@ func VolumeResource() *schema.Resource {
 }
 
 func createVolume(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VolumeResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -101,7 +105,10 @@ func createVolume(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readVolume(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VolumeResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -109,7 +116,10 @@ func readVolume(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func updateVolume(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VolumeResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -117,7 +127,10 @@ func updateVolume(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteVolume(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VolumeResourceCrud{}
 	sync.D = d
 	sync.Client = client.clientWithoutNotFoundRetries
@@ -204,6 +217,7 @@ func (s *VolumeResourceCrud) Get() (e error) {
 
 func (s *VolumeResourceCrud) Update() (e error) {
 	opts := &baremetal.UpdateOptions{}
+	opts.IfMatch = s.IfMatch()
 	displayName, ok := s.D.GetOk("display_name")
 	if ok {
 		opts.DisplayName = displayName.(string)
@@ -232,5 +246,5 @@ func (s *VolumeResourceCrud) SetData() {
 }
 
 func (s *VolumeResourceCrud) Delete() (e error) {
-	return s.Client.DeleteVolume(s.D.Id(), nil)
+	return s.Client.DeleteVolume(s.D.Id(), s.IfMatchOptions())
 }
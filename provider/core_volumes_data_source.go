@@ -44,7 +44,10 @@ func VolumeDatasource() *schema.Resource {
 }
 
 func readVolumes(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &VolumeDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
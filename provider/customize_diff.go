@@ -0,0 +1,75 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// customizeDiffFunc adjusts the planned diff of a resource, e.g. to force its
+// replacement when only some keys of a map change, which a schema alone
+// cannot express, or rejects it. s is nil when the resource is being
+// created, and meta is the configured provider's *OracleClients, or nil
+// before the provider is configured.
+type customizeDiffFunc func(s *terraform.InstanceState, c *terraform.ResourceConfig, diff *terraform.InstanceDiff, meta interface{}) error
+
+// diffCustomizingProvider is the provider, with the diffs of the resources in
+// diffCustomizers run through them.
+type diffCustomizingProvider struct {
+	*schema.Provider
+	diffCustomizers map[string]customizeDiffFunc
+}
+
+func (p *diffCustomizingProvider) Diff(info *terraform.InstanceInfo, s *terraform.InstanceState, c *terraform.ResourceConfig) (*terraform.InstanceDiff, error) {
+	diff, err := p.Provider.Diff(info, s, c)
+	customize, ok := p.diffCustomizers[info.Type]
+	if err != nil || diff == nil || !ok {
+		return diff, err
+	}
+
+	requiredNew := diff.RequiresNew()
+	if err = customize(s, c, diff, p.Meta()); err != nil {
+		return nil, err
+	}
+	if requiredNew || !diff.RequiresNew() {
+		return diff, nil
+	}
+	return p.replacementDiff(info, s, c, diff)
+}
+
+// replacementDiff turns diff, which a customizer has made force a new
+// resource, into a diff that creates the resource from scratch, as
+// helper/schema does for ForceNew attributes. The attributes that force the
+// replacement are kept, so that the plan shows what caused it.
+func (p *diffCustomizingProvider) replacementDiff(info *terraform.InstanceInfo, s *terraform.InstanceState, c *terraform.ResourceConfig, diff *terraform.InstanceDiff) (*terraform.InstanceDiff, error) {
+	replacement, err := p.ResourcesMap[info.Type].Diff(nil, c)
+	if err != nil {
+		return nil, err
+	}
+	if replacement == nil {
+		replacement = new(terraform.InstanceDiff)
+	}
+	if replacement.Attributes == nil {
+		replacement.Attributes = make(map[string]*terraform.ResourceAttrDiff)
+	}
+	replacement.DestroyTainted = diff.DestroyTainted
+
+	for k, attr := range replacement.Attributes {
+		attr.RequiresNew = false
+		if s != nil {
+			attr.Old = s.Attributes[k]
+		}
+	}
+	for k, attr := range diff.Attributes {
+		newAttr, ok := replacement.Attributes[k]
+		if !ok {
+			newAttr = attr
+		}
+		if attr.RequiresNew {
+			newAttr.RequiresNew = true
+		}
+		replacement.Attributes[k] = newAttr
+	}
+	return replacement, nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func instanceDiff(t *testing.T, metadata map[string]interface{}) *terraform.InstanceDiff {
+	p := Provider(func(d *schema.ResourceData) (interface{}, error) { return nil, nil })
+	state := &terraform.InstanceState{
+		ID: "ocid1.instance.oc1..standin",
+		Attributes: map[string]string{
+			"id":                    "ocid1.instance.oc1..standin",
+			"availability_domain":   "ad-1",
+			"compartment_id":        "ocid1.compartment.oc1..standin",
+			"image":                 "ocid1.image.oc1..standin",
+			"shape":                 "VM.Standard1.1",
+			"private_ip":            "10.0.0.2",
+			"metadata.%":            "2",
+			"metadata.user_data":    "Y2xvdWQtaW5pdA==",
+			"metadata.build_number": "41",
+		},
+	}
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"availability_domain": "ad-1",
+		"compartment_id":      "ocid1.compartment.oc1..standin",
+		"image":               "ocid1.image.oc1..standin",
+		"shape":               "VM.Standard1.1",
+		"metadata":            metadata,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := p.Diff(&terraform.InstanceInfo{Type: "oci_core_instance"}, state, terraform.NewResourceConfig(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return diff
+}
+
+func TestDiffCustomizingProvider_instanceMetadata(t *testing.T) {
+	// Ordinary keys are updated in place.
+	diff := instanceDiff(t, map[string]interface{}{"user_data": "Y2xvdWQtaW5pdA==", "build_number": "42"})
+	if assert.NotNil(t, diff) {
+		assert.False(t, diff.RequiresNew())
+		assert.Equal(t, "42", diff.Attributes["metadata.build_number"].New)
+		assert.Nil(t, diff.Attributes["id"])
+	}
+
+	// Changing user_data replaces the instance, and the plan says why.
+	diff = instanceDiff(t, map[string]interface{}{"user_data": "bmV3LWluaXQ=", "build_number": "42"})
+	if assert.NotNil(t, diff) {
+		assert.True(t, diff.RequiresNew())
+		assert.True(t, diff.Attributes["metadata.user_data"].RequiresNew)
+		assert.False(t, diff.Attributes["metadata.build_number"].RequiresNew)
+		// The replacement's computed attributes are unknown until it exists.
+		if assert.NotNil(t, diff.Attributes["private_ip"]) {
+			assert.True(t, diff.Attributes["private_ip"].NewComputed)
+			assert.Equal(t, "10.0.0.2", diff.Attributes["private_ip"].Old)
+		}
+	}
+
+	// So does adding ssh_authorized_keys.
+	diff = instanceDiff(t, map[string]interface{}{"user_data": "Y2xvdWQtaW5pdA==", "build_number": "41", "ssh_authorized_keys": "ssh-rsa AAAA"})
+	if assert.NotNil(t, diff) {
+		assert.True(t, diff.RequiresNew())
+		assert.True(t, diff.Attributes["metadata.ssh_authorized_keys"].RequiresNew)
+	}
+}
+
+func TestDiffCustomizingProvider_subnetCIDRBlock(t *testing.T) {
+	clients, closeServer, err := newStandInClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if !strings.HasSuffix(r.URL.Path, "/vcns/ocid1.vcn.oc1..standin") {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":"NotAuthorizedOrNotFound","message":"not found"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"ocid1.vcn.oc1..standin","cidrBlock":"10.0.0.0/16","lifecycleState":"AVAILABLE"}`))
+	}, func(d *schema.ResourceData) {
+		d.Set("disable_auto_retries", true)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	p := Provider(func(d *schema.ResourceData) (interface{}, error) { return nil, nil })
+	p.(*diffCustomizingProvider).SetMeta(clients)
+	subnetDiff := func(vcnID, cidrBlock string) error {
+		raw, err := config.NewRawConfig(map[string]interface{}{
+			"availability_domain": "ad-1",
+			"compartment_id":      "ocid1.compartment.oc1..standin",
+			"vcn_id":              vcnID,
+			"cidr_block":          cidrBlock,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = p.Diff(&terraform.InstanceInfo{Type: "oci_core_subnet"}, nil, terraform.NewResourceConfig(raw))
+		return err
+	}
+
+	assert.Nil(t, subnetDiff("ocid1.vcn.oc1..standin", "10.0.1.0/24"))
+	if err := subnetDiff("ocid1.vcn.oc1..standin", "10.1.0.0/24"); assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "not within 10.0.0.0/16")
+	}
+	// A VCN that can't be read is left for the apply to report.
+	assert.Nil(t, subnetDiff("ocid1.vcn.oc1..missing", "10.1.0.0/24"))
+}
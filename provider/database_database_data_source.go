@@ -70,7 +70,10 @@ func DatabaseDatasource() *schema.Resource {
 }
 
 func readDatabase(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DatabaseDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -43,7 +43,10 @@ func DatabasesDatasource() *schema.Resource {
 }
 
 func readDatabases(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DatabasesDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -50,7 +50,10 @@ func DBHomeDatasource() *schema.Resource {
 }
 
 func readDBHome(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DBHomeDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
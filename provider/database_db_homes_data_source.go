@@ -43,7 +43,10 @@ func DBHomesDatasource() *schema.Resource {
 }
 
 func readDBHomes(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DBHomesDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
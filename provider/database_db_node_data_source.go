@@ -50,7 +50,10 @@ func DBNodeDatasource() *schema.Resource {
 }
 
 func readDBNode(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DBNodeDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
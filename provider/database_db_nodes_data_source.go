@@ -43,7 +43,10 @@ func DBNodesDatasource() *schema.Resource {
 }
 
 func readDBNodes(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DBNodesDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
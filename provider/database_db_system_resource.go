@@ -35,6 +35,7 @@ func DBSystemResource() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			"etag": crud.ETagSchema(),
 			"shape": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -246,7 +247,10 @@ func DBSystemResource() *schema.Resource {
 }
 
 func createDBSystem(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DBSystemResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -254,7 +258,10 @@ func createDBSystem(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readDBSystem(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DBSystemResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -262,7 +269,10 @@ func readDBSystem(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteDBSystem(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DBSystemResourceCrud{}
 	sync.D = d
 	sync.Client = client.clientWithoutNotFoundRetries
@@ -434,5 +444,5 @@ func (s *DBSystemResourceCrud) SetData() {
 }
 
 func (s *DBSystemResourceCrud) Delete() (e error) {
-	return s.Client.TerminateDBSystem(s.D.Id(), nil)
+	return s.Client.TerminateDBSystem(s.D.Id(), s.IfMatchOptions())
 }
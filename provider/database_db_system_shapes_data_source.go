@@ -58,7 +58,10 @@ func DBSystemShapeDatasource() *schema.Resource {
 }
 
 func readDBSystemShapes(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &DBSystemShapeDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
@@ -39,7 +39,10 @@ func DBSystemDatasource() *schema.Resource {
 }
 
 func readDBSystems(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &DBSystemDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
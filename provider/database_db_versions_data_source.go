@@ -50,7 +50,10 @@ func DBVersionDatasource() *schema.Resource {
 }
 
 func readDBVersions(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &DBVersionDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
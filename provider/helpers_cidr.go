@@ -0,0 +1,56 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// validateCIDRBlock accepts IPv4 CIDR blocks whose address is the network
+// address, e.g. "10.0.1.0/24" but not "10.0.1.1/24", which the service
+// rejects or, worse, silently reads as another network.
+func validateCIDRBlock(i interface{}, k string) (s []string, es []error) {
+	if _, err := parseCIDRBlock(i.(string)); err != nil {
+		es = append(es, fmt.Errorf("%s: %s", k, err))
+	}
+	return
+}
+
+func parseCIDRBlock(cidrBlock string) (*net.IPNet, error) {
+	ip, network, err := net.ParseCIDR(cidrBlock)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a valid CIDR block", cidrBlock)
+	}
+	if ip.To4() == nil {
+		return nil, fmt.Errorf("%q is not an IPv4 CIDR block", cidrBlock)
+	}
+	if !ip.Equal(network.IP) {
+		return nil, fmt.Errorf("%q is not a network address, did you mean %q?", cidrBlock, network.String())
+	}
+	return network, nil
+}
+
+// cidrContains is true when inner is entirely within outer.
+func cidrContains(outer, inner *net.IPNet) bool {
+	outerOnes, _ := outer.Mask.Size()
+	innerOnes, _ := inner.Mask.Size()
+	return innerOnes >= outerOnes && outer.Contains(inner.IP)
+}
+
+// cidrOverlaps is true when a and b have addresses in common, i.e. when one
+// contains the other.
+func cidrOverlaps(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+func ipv4ToUint32(ip net.IP) uint32 {
+	return binary.BigEndian.Uint32(ip.To4())
+}
+
+func uint32ToIPv4(n uint32) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, n)
+	return ip
+}
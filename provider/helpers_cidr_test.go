@@ -0,0 +1,47 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCIDRBlock(t *testing.T) {
+	for _, valid := range []string{"10.0.0.0/16", "10.0.1.0/24", "0.0.0.0/0", "192.168.1.4/30", "10.0.0.1/32"} {
+		_, errs := validateCIDRBlock(valid, "cidr_block")
+		assert.Empty(t, errs, valid)
+	}
+
+	for _, invalid := range []string{"", "10.0.0.0", "10.0.0.0/33", "10.0.0/16", "10.0.0.0/16 ", "2001:db8::/32"} {
+		_, errs := validateCIDRBlock(invalid, "cidr_block")
+		assert.Len(t, errs, 1, invalid)
+	}
+
+	// Addresses within the network are a typo for the network address.
+	_, errs := validateCIDRBlock("10.0.1.1/24", "cidr_block")
+	if assert.Len(t, errs, 1) {
+		assert.Contains(t, errs[0].Error(), `did you mean "10.0.1.0/24"?`)
+	}
+}
+
+func TestCIDRContainsAndOverlaps(t *testing.T) {
+	parse := func(cidrBlock string) *net.IPNet {
+		n, err := parseCIDRBlock(cidrBlock)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	vcn := parse("10.0.0.0/16")
+	assert.True(t, cidrContains(vcn, parse("10.0.0.0/16")))
+	assert.True(t, cidrContains(vcn, parse("10.0.255.0/24")))
+	assert.False(t, cidrContains(vcn, parse("10.1.0.0/24")))
+	assert.False(t, cidrContains(vcn, parse("10.0.0.0/8")))
+
+	assert.True(t, cidrOverlaps(parse("10.0.0.0/8"), parse("10.0.1.0/24")))
+	assert.True(t, cidrOverlaps(parse("10.0.1.0/24"), parse("10.0.0.0/8")))
+	assert.False(t, cidrOverlaps(parse("10.0.0.0/24"), parse("10.0.1.0/24")))
+}
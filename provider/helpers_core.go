@@ -3,6 +3,8 @@
 package provider
 
 import (
+	"strings"
+
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/oracle/bmcs-go-sdk"
 )
@@ -44,6 +46,61 @@ var createVnicDetailsSchema = &schema.Resource{
 	},
 }
 
+// secondaryVnicSchema is a VNIC that an instance attaches after launch. Unlike
+// create_vnic_details, changing its subnet, private IP or public IP replaces
+// only the VNIC, by detaching it and attaching a new one, not the instance.
+var secondaryVnicSchema = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"assign_public_ip": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"display_name": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"hostname_label": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"private_ip": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		// Secondary private IP addresses, in addition to private_ip.
+		"private_ips": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"public_ip": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"skip_source_dest_check": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"subnet_id": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"vnic_attachment_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"vnic_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	},
+}
+
 // vnicDetailsList is assumed to be non-nil and non-empty.
 func SetCreateVnicOptions(vnicDetailsList []interface{}) (vnicOpts *baremetal.CreateVnicOptions) {
 	vnic := vnicDetailsList[0].(map[string]interface{})
@@ -115,3 +172,13 @@ func RefreshCreateVnicDetails(resourceData *schema.ResourceData, vnic *baremetal
 	vnicDetails["skip_source_dest_check"] = vnic.SkipSourceDestCheck
 	resourceData.Set("create_vnic_details", []interface{}{vnicDetails})
 }
+
+// ocidResourceType is the type of the resource an OCID identifies, e.g.
+// "drg" for "ocid1.drg.oc1.phx.aaaa...", or "" if id isn't an OCID.
+func ocidResourceType(id string) string {
+	parts := strings.Split(id, ".")
+	if len(parts) < 3 || !strings.HasPrefix(parts[0], "ocid") {
+		return ""
+	}
+	return parts[1]
+}
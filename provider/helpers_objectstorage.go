@@ -2,6 +2,10 @@
 
 package provider
 
+import (
+	"github.com/oracle/bmcs-go-sdk"
+)
+
 func resourceObjectStorageMapToMetadata(rm map[string]interface{}) map[string]string {
 	result := map[string]string{}
 	for k, v := range rm {
@@ -9,3 +13,17 @@ func resourceObjectStorageMapToMetadata(rm map[string]interface{}) map[string]st
 	}
 	return result
 }
+
+// resolveObjectStorageNamespace returns namespace, or, if it is empty, the
+// Object Storage namespace of the tenancy the client is configured for.
+func resolveObjectStorageNamespace(client *baremetal.Client, namespace string) (string, error) {
+	if namespace != "" {
+		return namespace, nil
+	}
+	reader := &NamespaceDatasourceCrud{}
+	reader.Client = client
+	if err := reader.Get(); err != nil {
+		return "", err
+	}
+	return string(*reader.Res), nil
+}
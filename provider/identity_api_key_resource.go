@@ -3,7 +3,6 @@
 package provider
 
 import (
-	"errors"
 	"regexp"
 
 	"github.com/hashicorp/terraform/helper/schema"
@@ -66,7 +65,10 @@ func APIKeyResource() *schema.Resource {
 }
 
 func createAPIKey(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &APIKeyResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -74,7 +76,10 @@ func createAPIKey(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readAPIKey(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &APIKeyResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -82,7 +87,10 @@ func readAPIKey(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteAPIKey(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &APIKeyResourceCrud{}
 	sync.D = d
 	sync.Client = client.clientWithoutNotFoundRetries
@@ -145,7 +153,7 @@ func (s *APIKeyResourceCrud) Get() (e error) {
 		}
 	}
 
-	return errors.New("Specified APIKEY does not exist")
+	return crud.NewMissingResourceError("Specified APIKEY does not exist")
 }
 
 func (s *APIKeyResourceCrud) SetData() {
@@ -29,7 +29,10 @@ func APIKeyDatasource() *schema.Resource {
 }
 
 func readAPIKeys(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &APIKeyDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
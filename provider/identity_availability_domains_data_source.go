@@ -42,7 +42,10 @@ func AvailabilityDomainDatasource() *schema.Resource {
 }
 
 func readAvailabilityDomains(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &AvailabilityDomainDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -25,6 +25,7 @@ func CompartmentResource() *schema.Resource {
 		Update:   updateCompartment,
 		Delete:   deleteCompartment,
 		Schema: map[string]*schema.Schema{
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -62,7 +63,10 @@ func CompartmentResource() *schema.Resource {
 }
 
 func createCompartment(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &CompartmentResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -70,7 +74,10 @@ func createCompartment(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readCompartment(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &CompartmentResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -78,7 +85,10 @@ func readCompartment(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func updateCompartment(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &CompartmentResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -166,6 +176,7 @@ func (s *CompartmentResourceCrud) Get() (e error) {
 
 func (s *CompartmentResourceCrud) Update() (e error) {
 	opts := &baremetal.UpdateCompartmentOptions{}
+	opts.IfMatch = s.IfMatch()
 	if name, ok := s.D.GetOk("name"); ok {
 		opts.Name = name.(string)
 	}
@@ -31,7 +31,10 @@ func CompartmentDatasource() *schema.Resource {
 }
 
 func readCompartments(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &CompartmentDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
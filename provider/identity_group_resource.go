@@ -23,6 +23,7 @@ func GroupResource() *schema.Resource {
 		Update:   updateGroup,
 		Delete:   deleteGroup,
 		Schema: map[string]*schema.Schema{
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -62,7 +63,10 @@ func GroupResource() *schema.Resource {
 }
 
 func createGroup(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &GroupSync{}
 	sync.D = d
 	sync.Client = client.client
@@ -70,7 +74,10 @@ func createGroup(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readGroup(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &GroupSync{}
 	sync.D = d
 	sync.Client = client.client
@@ -78,7 +85,10 @@ func readGroup(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func updateGroup(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &GroupSync{}
 	sync.D = d
 	sync.Client = client.client
@@ -86,7 +96,10 @@ func updateGroup(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteGroup(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &GroupSync{}
 	sync.D = d
 	sync.Client = client.clientWithoutNotFoundRetries
@@ -144,6 +157,7 @@ func (s *GroupSync) Get() (e error) {
 
 func (s *GroupSync) Update() (e error) {
 	opts := &baremetal.UpdateIdentityOptions{}
+	opts.IfMatch = s.IfMatch()
 	if description, ok := s.D.GetOk("description"); ok {
 		opts.Description = description.(string)
 	}
@@ -161,5 +175,5 @@ func (s *GroupSync) SetData() {
 }
 
 func (s *GroupSync) Delete() (e error) {
-	return s.Client.DeleteGroup(s.D.Id(), nil)
+	return s.Client.DeleteGroup(s.D.Id(), s.IfMatchOptions())
 }
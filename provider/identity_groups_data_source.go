@@ -31,7 +31,10 @@ func GroupDatasource() *schema.Resource {
 }
 
 func readGroups(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &GroupDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
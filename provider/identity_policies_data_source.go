@@ -31,7 +31,10 @@ func IdentityPolicyDatasource() *schema.Resource {
 }
 
 func readIdentityPolicies(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &IdentityPolicyDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -24,6 +24,7 @@ func PolicyResource() *schema.Resource {
 		Update:   updatePolicy,
 		Delete:   deletePolicy,
 		Schema: map[string]*schema.Schema{
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -105,7 +106,10 @@ func getOrDefault(d *schema.ResourceData, key string, defaultValue string) strin
 }
 
 func createPolicy(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &PolicyResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -113,7 +117,10 @@ func createPolicy(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readPolicy(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &PolicyResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -121,7 +128,10 @@ func readPolicy(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func updatePolicy(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &PolicyResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -129,7 +139,10 @@ func updatePolicy(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deletePolicy(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &PolicyResourceCrud{}
 	sync.D = d
 	sync.Client = client.clientWithoutNotFoundRetries
@@ -201,6 +214,7 @@ func (s *PolicyResourceCrud) Get() (e error) {
 
 func (s *PolicyResourceCrud) Update() (e error) {
 	opts := &baremetal.UpdatePolicyOptions{}
+	opts.IfMatch = s.IfMatch()
 	if description, ok := s.D.GetOk("description"); ok {
 		opts.Description = description.(string)
 	}
@@ -236,5 +250,5 @@ func getMD5Hash(values []string) string {
 }
 
 func (s *PolicyResourceCrud) Delete() (e error) {
-	return s.Client.DeletePolicy(s.D.Id(), nil)
+	return s.Client.DeletePolicy(s.D.Id(), s.IfMatchOptions())
 }
@@ -58,7 +58,10 @@ func SwiftPasswordResource() *schema.Resource {
 }
 
 func createSwiftPassword(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &SwiftPasswordResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -66,7 +69,10 @@ func createSwiftPassword(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readSwiftPassword(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &SwiftPasswordResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -74,7 +80,10 @@ func readSwiftPassword(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func updateSwiftPassword(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &SwiftPasswordResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -82,7 +91,10 @@ func updateSwiftPassword(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteSwiftPassword(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &SwiftPasswordResourceCrud{}
 	sync.D = d
 	sync.Client = client.clientWithoutNotFoundRetries
@@ -30,7 +30,10 @@ func SwiftPasswordDatasource() *schema.Resource {
 }
 
 func readSwiftPasswords(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &SwiftPasswordDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
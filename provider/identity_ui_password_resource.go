@@ -47,7 +47,10 @@ func UIPasswordResource() *schema.Resource {
 }
 
 func createUIPassword(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &UIPasswordResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -19,6 +19,7 @@ func UserGroupMembershipResource() *schema.Resource {
 		Read:     readUserGroupMembership,
 		Delete:   deleteUserGroupMembership,
 		Schema: map[string]*schema.Schema{
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -56,7 +57,10 @@ func UserGroupMembershipResource() *schema.Resource {
 }
 
 func createUserGroupMembership(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &UserGroupMembershipResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -64,7 +68,10 @@ func createUserGroupMembership(d *schema.ResourceData, m interface{}) (e error)
 }
 
 func readUserGroupMembership(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &UserGroupMembershipResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -72,7 +79,10 @@ func readUserGroupMembership(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteUserGroupMembership(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &UserGroupMembershipResourceCrud{}
 	sync.D = d
 	sync.Client = client.clientWithoutNotFoundRetries
@@ -134,5 +144,5 @@ func (s *UserGroupMembershipResourceCrud) SetData() {
 }
 
 func (s *UserGroupMembershipResourceCrud) Delete() (e error) {
-	return s.Client.DeleteUserGroupMembership(s.D.Id(), nil)
+	return s.Client.DeleteUserGroupMembership(s.D.Id(), s.IfMatchOptions())
 }
@@ -39,7 +39,10 @@ func UserGroupMembershipDatasource() *schema.Resource {
 }
 
 func readUserGroupMemberships(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &UserGroupMembershipDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -21,6 +21,7 @@ func UserResource() *schema.Resource {
 		Update:   updateUser,
 		Delete:   deleteUser,
 		Schema: map[string]*schema.Schema{
+			"etag": crud.ETagSchema(),
 			"id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -60,7 +61,10 @@ func UserResource() *schema.Resource {
 }
 
 func createUser(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &UserResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -68,7 +72,10 @@ func createUser(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func readUser(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &UserResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -76,7 +83,10 @@ func readUser(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func updateUser(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &UserResourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -84,7 +94,10 @@ func updateUser(d *schema.ResourceData, m interface{}) (e error) {
 }
 
 func deleteUser(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &UserResourceCrud{}
 	sync.D = d
 	sync.Client = client.clientWithoutNotFoundRetries
@@ -138,6 +151,7 @@ func (s *UserResourceCrud) Get() (e error) {
 
 func (s *UserResourceCrud) Update() (e error) {
 	opts := &baremetal.UpdateIdentityOptions{}
+	opts.IfMatch = s.IfMatch()
 	if description, ok := s.D.GetOk("description"); ok {
 		opts.Description = description.(string)
 	}
@@ -155,5 +169,5 @@ func (s *UserResourceCrud) SetData() {
 }
 
 func (s *UserResourceCrud) Delete() (e error) {
-	return s.Client.DeleteUser(s.D.Id(), nil)
+	return s.Client.DeleteUser(s.D.Id(), s.IfMatchOptions())
 }
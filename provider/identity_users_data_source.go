@@ -31,7 +31,10 @@ func UserDatasource() *schema.Resource {
 }
 
 func readUsers(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &UserDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -0,0 +1,125 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testInstancePrincipalTenancy = "ocid1.tenancy.oc1..instanceprincipal"
+
+func newTestCertificate(t *testing.T, ou string) (certPEM, keyPEM []byte) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{OrganizationalUnit: []string{ou}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return
+}
+
+func newTestSessionToken(expiry time.Time) string {
+	encode := func(v string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(v))
+	}
+	return encode(`{"alg":"RS256"}`) + "." + encode(fmt.Sprintf(`{"exp":%d}`, expiry.Unix())) + ".signature"
+}
+
+// newInstancePrincipalStandIn serves the instance metadata, federation and
+// compute endpoints that an instance principal client talks to.
+func newInstancePrincipalStandIn(t *testing.T) (server *httptest.Server, federations *int) {
+	leafCert, leafKey := newTestCertificate(t, "opc-tenant:"+testInstancePrincipalTenancy)
+	intermediateCert, _ := newTestCertificate(t, "intermediate")
+	token := newTestSessionToken(time.Now().Add(time.Hour))
+	federations = new(int)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metadata/identity/cert.pem", func(w http.ResponseWriter, r *http.Request) { w.Write(leafCert) })
+	mux.HandleFunc("/metadata/identity/key.pem", func(w http.ResponseWriter, r *http.Request) { w.Write(leafKey) })
+	mux.HandleFunc("/metadata/identity/intermediate.pem", func(w http.ResponseWriter, r *http.Request) { w.Write(intermediateCert) })
+	mux.HandleFunc("/federation/v1/x509", func(w http.ResponseWriter, r *http.Request) {
+		*federations++
+		if !strings.Contains(r.Header.Get("authorization"), `keyId="`+testInstancePrincipalTenancy+`/fed-x509/`) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"token": token})
+	})
+	mux.HandleFunc("/iaas/us-phoenix-1/20160918/instances/ocid1.instance.oc1..standin", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("authorization"), `keyId="ST$`+token+`"`) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("content-type", "application/json")
+		w.Write([]byte(`{"id":"ocid1.instance.oc1..standin","lifecycleState":"RUNNING"}`))
+	})
+
+	return httptest.NewServer(mux), federations
+}
+
+func TestProviderConfig_instancePrincipal(t *testing.T) {
+	server, federations := newInstancePrincipalStandIn(t)
+	defer server.Close()
+
+	os.Setenv("OCI_url_template", server.URL+"/%s/%s")
+	defer os.Unsetenv("OCI_url_template")
+
+	d := newProviderResourceData()
+	d.Set("auth", authInstancePrincipal)
+	d.Set("region", "us-phoenix-1")
+	d.Set("instance_metadata_url", server.URL+"/metadata")
+	d.Set("federation_url", server.URL+"/federation")
+
+	clients, err := ProviderConfig(d)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// Requests are signed with the session token instead of an API key.
+	instance, err := clients.(*OracleClients).client.GetInstance("ocid1.instance.oc1..standin")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "RUNNING", instance.State)
+	}
+
+	// Every client, in every region, shares one session token.
+	_, err = clients.(*OracleClients).ForRegion("us-ashburn-1")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, *federations)
+}
+
+func TestProviderConfig_instancePrincipalUnreachableMetadata(t *testing.T) {
+	d := newProviderResourceData()
+	d.Set("auth", authInstancePrincipal)
+	d.Set("instance_metadata_url", "http://127.0.0.1:1/opc/v1")
+
+	_, err := ProviderConfig(d)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "instance metadata service")
+	}
+}
@@ -68,36 +68,54 @@ func LoadBalancerBackendResource() *schema.Resource {
 }
 
 func createLoadBalancerBackend(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerBackendResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
+	sync.RetryPolicy = client.workRequestRetryPolicy
 	return crud.CreateResource(d, sync)
 }
 
 func readLoadBalancerBackend(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerBackendResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.ReadResource(sync)
 }
 
 func updateLoadBalancerBackend(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerBackendResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.UpdateResource(d, sync)
 }
 
 func deleteLoadBalancerBackend(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerBackendResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).clientWithoutNotFoundRetries
+	sync.Client = client.clientWithoutNotFoundRetries
 	return crud.DeleteResource(d, sync)
 }
 
 type LoadBalancerBackendResourceCrud struct {
 	crud.BaseCrud
 	WorkRequest *baremetal.WorkRequest
+	RetryPolicy *crud.WorkRequestRetryPolicy
 	Resource    *baremetal.Backend
 }
 
@@ -134,6 +152,14 @@ func (s *LoadBalancerBackendResourceCrud) CreatedTarget() []string {
 	}
 }
 
+func (s *LoadBalancerBackendResourceCrud) CreateRetryPolicy() *crud.WorkRequestRetryPolicy {
+	return s.RetryPolicy
+}
+
+func (s *LoadBalancerBackendResourceCrud) LastWorkRequest() *baremetal.WorkRequest {
+	return s.WorkRequest
+}
+
 func (s *LoadBalancerBackendResourceCrud) DeletedPending() []string {
 	return []string{
 		baremetal.ResourceWaitingForWorkRequest,
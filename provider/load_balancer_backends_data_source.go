@@ -33,7 +33,10 @@ func BackendDatasource() *schema.Resource {
 }
 
 func readBackends(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &BackendDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
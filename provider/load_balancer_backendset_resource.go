@@ -50,36 +50,54 @@ func LoadBalancerBackendSetResource() *schema.Resource {
 }
 
 func createLoadBalancerBackendSet(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerBackendSetResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
+	sync.RetryPolicy = client.workRequestRetryPolicy
 	return crud.CreateResource(d, sync)
 }
 
 func readLoadBalancerBackendSet(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerBackendSetResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.ReadResource(sync)
 }
 
 func updateLoadBalancerBackendSet(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerBackendSetResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.UpdateResource(d, sync)
 }
 
 func deleteLoadBalancerBackendSet(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerBackendSetResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).clientWithoutNotFoundRetries
+	sync.Client = client.clientWithoutNotFoundRetries
 	return crud.DeleteResource(d, sync)
 }
 
 type LoadBalancerBackendSetResourceCrud struct {
 	crud.BaseCrud
 	WorkRequest  *baremetal.WorkRequest
+	RetryPolicy  *crud.WorkRequestRetryPolicy
 	Resource     *baremetal.BackendSet
 	ResourceName string
 }
@@ -111,6 +129,14 @@ func (s *LoadBalancerBackendSetResourceCrud) CreatedTarget() []string {
 	}
 }
 
+func (s *LoadBalancerBackendSetResourceCrud) CreateRetryPolicy() *crud.WorkRequestRetryPolicy {
+	return s.RetryPolicy
+}
+
+func (s *LoadBalancerBackendSetResourceCrud) LastWorkRequest() *baremetal.WorkRequest {
+	return s.WorkRequest
+}
+
 func (s *LoadBalancerBackendSetResourceCrud) DeletedPending() []string {
 	return []string{
 		baremetal.ResourceWaitingForWorkRequest,
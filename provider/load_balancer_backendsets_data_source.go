@@ -30,7 +30,10 @@ func BackendSetDatasource() *schema.Resource {
 }
 
 func readBackendSets(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &BackendSetDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -3,8 +3,6 @@
 package provider
 
 import (
-	"errors"
-
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/oracle/bmcs-go-sdk"
 
@@ -58,29 +56,43 @@ func LoadBalancerCertificateResource() *schema.Resource {
 }
 
 func createLoadBalancerCertificate(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerCertificateResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
+	sync.RetryPolicy = client.workRequestRetryPolicy
 	return crud.CreateResource(d, sync)
 }
 
 func readLoadBalancerCertificate(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerCertificateResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.ReadResource(sync)
 }
 
 func deleteLoadBalancerCertificate(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerCertificateResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).clientWithoutNotFoundRetries
+	sync.Client = client.clientWithoutNotFoundRetries
 	return crud.DeleteResource(d, sync)
 }
 
 type LoadBalancerCertificateResourceCrud struct {
 	crud.BaseCrud
 	WorkRequest *baremetal.WorkRequest
+	RetryPolicy *crud.WorkRequestRetryPolicy
 	Resource    *baremetal.Certificate
 }
 
@@ -111,6 +123,14 @@ func (s *LoadBalancerCertificateResourceCrud) CreatedTarget() []string {
 	}
 }
 
+func (s *LoadBalancerCertificateResourceCrud) CreateRetryPolicy() *crud.WorkRequestRetryPolicy {
+	return s.RetryPolicy
+}
+
+func (s *LoadBalancerCertificateResourceCrud) LastWorkRequest() *baremetal.WorkRequest {
+	return s.WorkRequest
+}
+
 func (s *LoadBalancerCertificateResourceCrud) DeletedPending() []string {
 	return []string{
 		baremetal.ResourceWaitingForWorkRequest,
@@ -168,7 +188,7 @@ func (s *LoadBalancerCertificateResourceCrud) Get() (e error) {
 			return
 		}
 	}
-	e = errors.New("Certificate does not exist")
+	e = crud.NewMissingResourceError("Certificate does not exist")
 	return
 }
 
@@ -30,7 +30,10 @@ func CertificateDatasource() *schema.Resource {
 }
 
 func readCertificate(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &CertificateDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -3,7 +3,6 @@
 package provider
 
 import (
-	"fmt"
 	"log"
 
 	"github.com/hashicorp/terraform/helper/schema"
@@ -52,36 +51,54 @@ func LoadBalancerListenerResource() *schema.Resource {
 }
 
 func createLoadBalancerListener(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerListenerResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
+	sync.RetryPolicy = client.workRequestRetryPolicy
 	return crud.CreateResource(d, sync)
 }
 
 func readLoadBalancerListener(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerListenerResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.ReadResource(sync)
 }
 
 func updateLoadBalancerListener(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerListenerResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).client
+	sync.Client = client.client
 	return crud.UpdateResource(d, sync)
 }
 
 func deleteLoadBalancerListener(d *schema.ResourceData, m interface{}) (e error) {
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerListenerResourceCrud{}
 	sync.D = d
-	sync.Client = m.(*OracleClients).clientWithoutNotFoundRetries
+	sync.Client = client.clientWithoutNotFoundRetries
 	return crud.DeleteResource(d, sync)
 }
 
 type LoadBalancerListenerResourceCrud struct {
 	crud.BaseCrud
 	WorkRequest *baremetal.WorkRequest
+	RetryPolicy *crud.WorkRequestRetryPolicy
 	Resource    *baremetal.Listener
 }
 
@@ -113,6 +130,14 @@ func (s *LoadBalancerListenerResourceCrud) CreatedTarget() []string {
 	}
 }
 
+func (s *LoadBalancerListenerResourceCrud) CreateRetryPolicy() *crud.WorkRequestRetryPolicy {
+	return s.RetryPolicy
+}
+
+func (s *LoadBalancerListenerResourceCrud) LastWorkRequest() *baremetal.WorkRequest {
+	return s.WorkRequest
+}
+
 func (s *LoadBalancerListenerResourceCrud) DeletedPending() []string {
 	return []string{
 		baremetal.ResourceWaitingForWorkRequest,
@@ -188,7 +213,7 @@ func (s *LoadBalancerListenerResourceCrud) GetListener(loadBalancerID, name stri
 	if l.Name == name {
 		return &l, nil
 	}
-	return nil, fmt.Errorf("Listener %s on load balancer %s does not exist", name, loadBalancerID)
+	return nil, crud.NewMissingResourceError("Listener %s on load balancer %s does not exist", name, loadBalancerID)
 }
 
 func (s *LoadBalancerListenerResourceCrud) Update() (e error) {
@@ -37,7 +37,10 @@ func LoadBalancerPolicyDatasource() *schema.Resource {
 }
 
 func readPolicies(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &PoliciesDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
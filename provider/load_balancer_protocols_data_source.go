@@ -37,7 +37,10 @@ func ProtocolDatasource() *schema.Resource {
 }
 
 func readProtocols(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &ProtocolDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -37,7 +37,10 @@ func LoadBalancerShapeDatasource() *schema.Resource {
 }
 
 func readLoadBalancerShapes(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerShapeDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -31,7 +31,10 @@ func LoadBalancerDatasource() *schema.Resource {
 }
 
 func readLoadBalancers(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	sync := &LoadBalancerDatasourceCrud{}
 	sync.D = d
 	sync.Client = client.client
@@ -70,7 +70,10 @@ func BucketSummaryDatasource() *schema.Resource {
 }
 
 func readBucketSummaries(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &BucketSummaryDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
@@ -24,7 +24,10 @@ func NamespaceDatasource() *schema.Resource {
 }
 
 func readNamespace(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &NamespaceDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
@@ -44,7 +44,10 @@ func ObjectHeadDatasource() *schema.Resource {
 }
 
 func readObjectHead(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &ObjectHeadDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
@@ -74,7 +74,10 @@ func resourceObjectSummary() *schema.Resource {
 }
 
 func readObjects(d *schema.ResourceData, m interface{}) (e error) {
-	client := m.(*OracleClients)
+	client, e := clientsForRegion(d, m)
+	if e != nil {
+		return
+	}
 	reader := &ObjectDatasourceCrud{}
 	reader.D = d
 	reader.Client = client.client
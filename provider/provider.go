@@ -3,27 +3,49 @@
 package provider
 
 import (
-	"crypto/tls"
+	"context"
 	"errors"
 	"fmt"
-	"log"
-	"net/http"
+	"math"
 	"os"
+	"regexp"
 	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/oracle/bmcs-go-sdk"
+
+	"github.com/oracle/terraform-provider-oci/crud"
 )
 
 var descriptions map[string]string
 
+var retryOverrideKeyRegex = regexp.MustCompile(`^([1-5][0-9]{2})(?::([A-Za-z]+))?$`)
+
+const (
+	authAPIKey            = "ApiKey"
+	authInstancePrincipal = "InstancePrincipal"
+
+	defaultWorkRequestCreateRetries = 2
+	workRequestRetryBackoff         = 15 * time.Second
+	workRequestRetryMaxBackoff      = 2 * time.Minute
+)
+
 func init() {
 	descriptions = map[string]string{
-		"tenancy_ocid": "(Required) The tenancy OCID for a user. The tenancy OCID can be found at the bottom of user settings in the Oracle Cloud Infrastructure console.",
-		"user_ocid":    "(Required) The user OCID. This can be found in user settings in the Oracle Cloud Infrastructure console.",
-		"fingerprint":  "(Required) The fingerprint for the user's RSA key. This can be found in user settings in the Oracle Cloud Infrastructure console.",
-		"region":       "(Required) The region for API connections (e.g. us-ashburn-1).",
+		"tenancy_ocid": "(Required) The tenancy OCID for a user. The tenancy OCID can be found at the bottom of user settings in the Oracle Cloud Infrastructure console.\n" +
+			"May be omitted when config_file_profile supplies it.",
+		"user_ocid": "(Required) The user OCID. This can be found in user settings in the Oracle Cloud Infrastructure console.\n" +
+			"May be omitted when config_file_profile supplies it.",
+		"fingerprint": "(Required) The fingerprint for the user's RSA key. This can be found in user settings in the Oracle Cloud Infrastructure console.\n" +
+			"May be omitted when config_file_profile supplies it.",
+		"region": "(Required) The region for API connections (e.g. us-ashburn-1).\n" +
+			"May be omitted when config_file_profile supplies it.",
 		"private_key": "(Optional) A PEM formatted RSA private key for the user.\n" +
 			"A private_key or a private_key_path must be provided.",
 		"private_key_path": "(Optional) The path to the user's PEM formatted private key.\n" +
@@ -31,36 +53,66 @@ func init() {
 		"private_key_password": "(Optional) The password used to secure the private key.",
 		"disable_auto_retries": "(Optional) Disable Automatic retries for retriable errors.\n" +
 			"Auto retries were introduced to solve some eventual consistency problems but it also introduced performance issues on destroy operations.",
+		"auth": "(Optional) The type of authentication to use: " + authAPIKey + " (the default) signs requests with a user's API key, " +
+			authInstancePrincipal + " signs requests as the compute instance Terraform runs on, and needs no user credentials.",
+		"instance_metadata_url": "(Optional) With " + authInstancePrincipal + " auth, the URL of the instance metadata service to read the instance identity certificate from. " +
+			"Defaults to http://169.254.169.254/opc/v1.",
+		"federation_url": "(Optional) With " + authInstancePrincipal + " auth, the URL of the auth service that exchanges the instance identity certificate for a session token. " +
+			"Defaults to the auth service of the provider's region.",
+		"config_file_profile": "(Optional) The name of a profile in the OCI CLI config file to read credentials and region from.\n" +
+			"Provider arguments that are set explicitly override the values in the profile.",
+		"config_file_path": "(Optional) The path to the OCI CLI config file. Defaults to " + defaultConfigFilePath + ".",
+		"max_retry_duration_seconds": "(Optional) The longest time, in seconds, that a request failing with a retriable error is retried. " +
+			"Defaults to the SDK's retry times of 2 minutes, or 10 minutes for throttling and eventual consistency errors.",
+		"retry_backoff": "(Optional) How the wait between retries grows: " + string(baremetal.PolynomialBackoff) + " (the default) waits the square of the retry number in seconds, " +
+			string(baremetal.ExponentialBackoff) + " doubles the wait on every retry, up to a minute, with random jitter.",
+		"retry_duration_overrides": "(Optional) How long, in seconds, to retry requests that fail with specific errors, keyed by HTTP status " +
+			"(e.g. \"429\") or status and error code (e.g. \"409:NotAuthorizedOrResourceAlreadyExists\"). Overrides max_retry_duration_seconds; 0 disables the retries.",
+		"request_limit": "(Optional) Throttles the requests sent to one service (core, identity, database, objectstorage or loadbalancer). " +
+			"Every attempt at a request, including retries, counts against the limit.",
+		"ca_bundle_path": "(Optional) The path to a PEM file of CA certificates to trust in addition to the system's, " +
+			"e.g. that of a TLS-intercepting proxy.",
+		"proxy_url": "(Optional) The URL of a proxy to send requests through. " +
+			"Defaults to the proxy set by the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables, if any.",
+		"work_request_create_retries": "(Optional) The number of times a resource that is created through a work request (e.g. load balancer listeners, backend sets, backends and certificates) is resubmitted after its work request fails.",
 	}
 }
 
 // Provider is the adapter for terraform, that gives access to all the resources
 func Provider(configfn schema.ConfigureFunc) terraform.ResourceProvider {
-	return &schema.Provider{
+	p := &schema.Provider{
 		DataSourcesMap: dataSourcesMap(),
 		Schema:         schemaMap(),
 		ResourcesMap:   resourcesMap(),
-		ConfigureFunc:  configfn,
 	}
+	p.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		clients, err := configfn(d)
+		// API calls stop when Terraform is interrupted.
+		if oracleClients, ok := clients.(*OracleClients); ok {
+			oracleClients.stopContext = p.StopContext()
+		}
+		return clients, err
+	}
+	return &diffCustomizingProvider{Provider: p, diffCustomizers: resourceDiffCustomizers()}
 }
 
 func schemaMap() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"tenancy_ocid": {
 			Type:        schema.TypeString,
-			Required:    true,
+			Optional:    true,
 			Description: descriptions["tenancy_ocid"],
 			DefaultFunc: schema.EnvDefaultFunc("OCI_TENANCY_OCID", nil),
 		},
 		"user_ocid": {
 			Type:        schema.TypeString,
-			Required:    true,
+			Optional:    true,
 			Description: descriptions["user_ocid"],
 			DefaultFunc: schema.EnvDefaultFunc("OCI_USER_OCID", nil),
 		},
 		"fingerprint": {
 			Type:        schema.TypeString,
-			Required:    true,
+			Optional:    true,
 			Description: descriptions["fingerprint"],
 			DefaultFunc: schema.EnvDefaultFunc("OCI_FINGERPRINT", nil),
 		},
@@ -89,7 +141,7 @@ func schemaMap() map[string]*schema.Schema {
 		},
 		"region": {
 			Type:        schema.TypeString,
-			Required:    true,
+			Optional:    true,
 			Description: descriptions["region"],
 			DefaultFunc: schema.EnvDefaultFunc("OCI_REGION", nil),
 		},
@@ -100,16 +152,113 @@ func schemaMap() map[string]*schema.Schema {
 			Description: descriptions["disable_auto_retries"],
 			DefaultFunc: schema.EnvDefaultFunc("OCI_DISABLE_AUTO_RETRIES", nil),
 		},
+		"auth": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  descriptions["auth"],
+			DefaultFunc:  schema.EnvDefaultFunc("OCI_AUTH", authAPIKey),
+			ValidateFunc: validation.StringInSlice([]string{authAPIKey, authInstancePrincipal}, true),
+		},
+		"instance_metadata_url": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: descriptions["instance_metadata_url"],
+			DefaultFunc: schema.EnvDefaultFunc("OCI_INSTANCE_METADATA_URL", nil),
+		},
+		"federation_url": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: descriptions["federation_url"],
+			DefaultFunc: schema.EnvDefaultFunc("OCI_FEDERATION_URL", nil),
+		},
+		"config_file_profile": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: descriptions["config_file_profile"],
+			DefaultFunc: schema.EnvDefaultFunc("OCI_CONFIG_FILE_PROFILE", nil),
+		},
+		"config_file_path": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: descriptions["config_file_path"],
+			DefaultFunc: schema.EnvDefaultFunc("OCI_CONFIG_FILE", defaultConfigFilePath),
+		},
+		"max_retry_duration_seconds": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  descriptions["max_retry_duration_seconds"],
+			DefaultFunc:  schema.EnvDefaultFunc("OCI_MAX_RETRY_DURATION_SECONDS", 0),
+			ValidateFunc: validation.IntBetween(0, 24*60*60),
+		},
+		"retry_backoff": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  descriptions["retry_backoff"],
+			DefaultFunc:  schema.EnvDefaultFunc("OCI_RETRY_BACKOFF", string(baremetal.PolynomialBackoff)),
+			ValidateFunc: validation.StringInSlice([]string{string(baremetal.PolynomialBackoff), string(baremetal.ExponentialBackoff)}, true),
+		},
+		"retry_duration_overrides": {
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Description: descriptions["retry_duration_overrides"],
+			Elem:        schema.TypeInt,
+		},
+		"request_limit": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: descriptions["request_limit"],
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"service": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringInSlice(requestLimitServices(), false),
+					},
+					"requests_per_second": {
+						Type:     schema.TypeFloat,
+						Optional: true,
+					},
+					"burst": {
+						Type:     schema.TypeInt,
+						Optional: true,
+					},
+					"max_concurrent_requests": {
+						Type:     schema.TypeInt,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"ca_bundle_path": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: descriptions["ca_bundle_path"],
+			DefaultFunc: schema.EnvDefaultFunc("OCI_CA_BUNDLE", nil),
+		},
+		"proxy_url": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: descriptions["proxy_url"],
+			DefaultFunc: schema.EnvDefaultFunc("OCI_PROXY_URL", nil),
+		},
+		"work_request_create_retries": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  descriptions["work_request_create_retries"],
+			DefaultFunc:  schema.EnvDefaultFunc("OCI_WORK_REQUEST_CREATE_RETRIES", defaultWorkRequestCreateRetries),
+			ValidateFunc: validation.IntBetween(0, 10),
+		},
 	}
 }
 
 func dataSourcesMap() map[string]*schema.Resource {
-	return map[string]*schema.Resource{
+	return regionalDataSources(map[string]*schema.Resource{
 		"oci_core_console_history_data":       ConsoleHistoryDataDatasource(),
 		"oci_core_cpes":                       CpeDatasource(),
 		"oci_core_dhcp_options":               DHCPOptionsDatasource(),
 		"oci_core_drg_attachments":            DrgAttachmentDatasource(),
 		"oci_core_drgs":                       DrgDatasource(),
+		"oci_core_image":                      ImageLookupDatasource(),
 		"oci_core_images":                     ImageDatasource(),
 		"oci_core_instance_credentials":       InstanceCredentialsDatasource(),
 		"oci_core_instances":                  InstanceDatasource(),
@@ -121,7 +270,9 @@ func dataSourcesMap() map[string]*schema.Resource {
 		"oci_core_route_tables":               RouteTableDatasource(),
 		"oci_core_security_lists":             SecurityListDatasource(),
 		"oci_core_shape":                      InstanceShapeDatasource(),
+		"oci_core_subnet_cidr_plan":           SubnetCIDRPlanDatasource(),
 		"oci_core_subnets":                    SubnetDatasource(),
+		"oci_core_vcn_topology":               VCNTopologyDatasource(),
 		"oci_core_virtual_networks":           VirtualNetworkDatasource(),
 		"oci_core_vnic":                       VnicDatasource(),
 		"oci_core_vnic_attachments":           DatasourceCoreVnicAttachments(),
@@ -156,49 +307,62 @@ func dataSourcesMap() map[string]*schema.Resource {
 		"oci_objectstorage_namespace":         NamespaceDatasource(),
 		"oci_objectstorage_object_head":       ObjectHeadDatasource(),
 		"oci_objectstorage_objects":           ObjectDatasource(),
-	}
+	})
 }
 
 func resourcesMap() map[string]*schema.Resource {
-	return map[string]*schema.Resource{
-		"oci_core_console_history":           ConsoleHistoryResource(),
-		"oci_core_cpe":                       CpeResource(),
-		"oci_core_default_dhcp_options":      DefaultDHCPOptionsResource(),
-		"oci_core_dhcp_options":              DHCPOptionsResource(),
-		"oci_core_drg":                       DrgResource(),
-		"oci_core_drg_attachment":            DrgAttachmentResource(),
-		"oci_core_image":                     ImageResource(),
-		"oci_core_instance":                  InstanceResource(),
-		"oci_core_internet_gateway":          InternetGatewayResource(),
-		"oci_core_ipsec":                     IPSecConnectionResource(),
-		"oci_core_private_ip":                PrivateIPResource(),
-		"oci_core_default_route_table":       DefaultRouteTableResource(),
-		"oci_core_route_table":               RouteTableResource(),
-		"oci_core_default_security_list":     DefaultSecurityListResource(),
-		"oci_core_security_list":             SecurityListResource(),
-		"oci_core_subnet":                    SubnetResource(),
-		"oci_core_virtual_network":           VirtualNetworkResource(),
-		"oci_core_vnic_attachment":           VnicAttachmentResource(),
-		"oci_core_volume":                    VolumeResource(),
-		"oci_core_volume_attachment":         VolumeAttachmentResource(),
-		"oci_core_volume_backup":             VolumeBackupResource(),
-		"oci_database_db_system":             DBSystemResource(),
-		"oci_identity_api_key":               APIKeyResource(),
-		"oci_identity_compartment":           CompartmentResource(),
-		"oci_identity_group":                 GroupResource(),
-		"oci_identity_policy":                PolicyResource(),
-		"oci_identity_swift_password":        SwiftPasswordResource(),
-		"oci_identity_ui_password":           UIPasswordResource(),
-		"oci_identity_user":                  UserResource(),
-		"oci_identity_user_group_membership": UserGroupMembershipResource(),
-		"oci_load_balancer":                  LoadBalancerResource(),
-		"oci_load_balancer_backend":          LoadBalancerBackendResource(),
-		"oci_load_balancer_backendset":       LoadBalancerBackendSetResource(),
-		"oci_load_balancer_certificate":      LoadBalancerCertificateResource(),
-		"oci_load_balancer_listener":         LoadBalancerListenerResource(),
-		"oci_objectstorage_bucket":           BucketResource(),
-		"oci_objectstorage_object":           ObjectResource(),
-		"oci_objectstorage_preauthrequest":   PreauthenticatedRequestResource(),
+	return regionalResources(map[string]*schema.Resource{
+		"oci_core_console_history":             ConsoleHistoryResource(),
+		"oci_core_cpe":                         CpeResource(),
+		"oci_core_default_dhcp_options":        DefaultDHCPOptionsResource(),
+		"oci_core_dhcp_options":                DHCPOptionsResource(),
+		"oci_core_drg":                         DrgResource(),
+		"oci_core_drg_attachment":              DrgAttachmentResource(),
+		"oci_core_image":                       ImageResource(),
+		"oci_core_image_export":                ImageExportResource(),
+		"oci_core_instance":                    InstanceResource(),
+		"oci_core_instance_action":             InstanceActionResource(),
+		"oci_core_instance_console_connection": InstanceConsoleConnectionResource(),
+		"oci_core_internet_gateway":            InternetGatewayResource(),
+		"oci_core_ipsec":                       IPSecConnectionResource(),
+		"oci_core_private_ip":                  PrivateIPResource(),
+		"oci_core_default_route_table":         DefaultRouteTableResource(),
+		"oci_core_route_table":                 RouteTableResource(),
+		"oci_core_default_security_list":       DefaultSecurityListResource(),
+		"oci_core_security_list":               SecurityListResource(),
+		"oci_core_security_list_rule":          SecurityListRuleResource(),
+		"oci_core_subnet":                      SubnetResource(),
+		"oci_core_virtual_network":             VirtualNetworkResource(),
+		"oci_core_vnic_attachment":             VnicAttachmentResource(),
+		"oci_core_volume":                      VolumeResource(),
+		"oci_core_volume_attachment":           VolumeAttachmentResource(),
+		"oci_core_volume_backup":               VolumeBackupResource(),
+		"oci_database_db_system":               DBSystemResource(),
+		"oci_identity_api_key":                 APIKeyResource(),
+		"oci_identity_compartment":             CompartmentResource(),
+		"oci_identity_group":                   GroupResource(),
+		"oci_identity_policy":                  PolicyResource(),
+		"oci_identity_swift_password":          SwiftPasswordResource(),
+		"oci_identity_ui_password":             UIPasswordResource(),
+		"oci_identity_user":                    UserResource(),
+		"oci_identity_user_group_membership":   UserGroupMembershipResource(),
+		"oci_load_balancer":                    LoadBalancerResource(),
+		"oci_load_balancer_backend":            LoadBalancerBackendResource(),
+		"oci_load_balancer_backendset":         LoadBalancerBackendSetResource(),
+		"oci_load_balancer_certificate":        LoadBalancerCertificateResource(),
+		"oci_load_balancer_listener":           LoadBalancerListenerResource(),
+		"oci_objectstorage_bucket":             BucketResource(),
+		"oci_objectstorage_object":             ObjectResource(),
+		"oci_objectstorage_preauthrequest":     PreauthenticatedRequestResource(),
+	})
+}
+
+// resourceDiffCustomizers are the resources whose plans need more than their
+// schema to work out.
+func resourceDiffCustomizers() map[string]customizeDiffFunc {
+	return map[string]customizeDiffFunc{
+		"oci_core_instance": customizeInstanceDiff,
+		"oci_core_subnet":   customizeSubnetDiff,
 	}
 }
 
@@ -226,15 +390,135 @@ func getRequiredEnvSetting(s string) string {
 	return v
 }
 
-func ProviderConfig(d *schema.ResourceData) (clients interface{}, err error) {
-	tenancyOCID := d.Get("tenancy_ocid").(string)
-	userOCID := d.Get("user_ocid").(string)
-	fingerprint := d.Get("fingerprint").(string)
+// apiKeyCredentials resolves the user, tenancy and signing key used to
+// authenticate with a user's API key.
+func apiKeyCredentials(d *schema.ResourceData, profile *configFileProfile) (tenancyOCID, userOCID, fingerprint string, opts []baremetal.NewClientOptionsFunc, err error) {
+	if tenancyOCID, err = getRequiredProviderSetting(d, profile, "tenancy_ocid"); err != nil {
+		return
+	}
+	if userOCID, err = getRequiredProviderSetting(d, profile, "user_ocid"); err != nil {
+		return
+	}
+	if fingerprint, err = getRequiredProviderSetting(d, profile, "fingerprint"); err != nil {
+		return
+	}
 	privateKeyBuffer, hasKey := d.Get("private_key").(string)
-	privateKeyPath, hasKeyPath := d.Get("private_key_path").(string)
-	privateKeyPassword, hasKeyPass := d.Get("private_key_password").(string)
-	region, hasRegion := d.Get("region").(string)
+	privateKeyPath := getProviderSetting(d, profile, "private_key_path")
+	privateKeyPassword := getProviderSetting(d, profile, "private_key_password")
+
+	if hasKey && privateKeyBuffer != "" {
+		opts = append(opts, baremetal.PrivateKeyBytes([]byte(privateKeyBuffer)))
+	} else if privateKeyPath != "" {
+		opts = append(opts, baremetal.PrivateKeyFilePath(privateKeyPath))
+	} else if profile != nil {
+		err = fmt.Errorf("One of private_key or private_key_path is required: neither is set on the provider and %q is missing from profile %q in %s",
+			configFileKeys["private_key_path"], profile.name, profile.path)
+		return
+	} else {
+		err = errors.New("One of private_key or private_key_path is required")
+		return
+	}
+
+	if privateKeyPassword != "" {
+		opts = append(opts, baremetal.PrivateKeyPassword(privateKeyPassword))
+	}
+	return
+}
+
+// retryPolicyOptions translates the retry provider arguments into client
+// options.
+func retryPolicyOptions(d *schema.ResourceData) (opts []baremetal.NewClientOptionsFunc, err error) {
+	if maxRetryDuration, ok := d.Get("max_retry_duration_seconds").(int); ok && maxRetryDuration > 0 {
+		opts = append(opts, baremetal.MaxRetryTime(time.Duration(maxRetryDuration)*time.Second))
+	}
+
+	if backoff, ok := d.Get("retry_backoff").(string); ok && strings.EqualFold(backoff, string(baremetal.ExponentialBackoff)) {
+		opts = append(opts, baremetal.RetryBackoffShape(baremetal.ExponentialBackoff))
+	}
+
+	overrides, _ := d.Get("retry_duration_overrides").(map[string]interface{})
+	for key, value := range overrides {
+		match := retryOverrideKeyRegex.FindStringSubmatch(key)
+		if match == nil {
+			return nil, fmt.Errorf("retry_duration_overrides: %q must be an HTTP status (e.g. \"429\") or a status and error code (e.g. \"409:NotAuthorizedOrResourceAlreadyExists\")", key)
+		}
+
+		var seconds int
+		switch v := value.(type) {
+		case int:
+			seconds = v
+		case string:
+			if seconds, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("retry_duration_overrides: %q must be a number of seconds, got %q", key, v)
+			}
+		}
+		if seconds < 0 {
+			return nil, fmt.Errorf("retry_duration_overrides: %q must not be negative", key)
+		}
+
+		opts = append(opts, baremetal.RetryTimeOverride(match[1], match[2], time.Duration(seconds)*time.Second))
+	}
+	return
+}
+
+func requestLimitServices() []string {
+	services := make([]string, len(baremetal.Services))
+	for i, service := range baremetal.Services {
+		services[i] = string(service)
+	}
+	return services
+}
+
+// requestLimiterOptions creates a request limiter for every request_limit
+// block.
+func requestLimiterOptions(d *schema.ResourceData) (opts []baremetal.NewClientOptionsFunc, err error) {
+	limits, _ := d.Get("request_limit").([]interface{})
+	seen := map[string]bool{}
+	for _, raw := range limits {
+		limit, _ := raw.(map[string]interface{})
+		service, _ := limit["service"].(string)
+		requestsPerSecond, _ := limit["requests_per_second"].(float64)
+		burst, _ := limit["burst"].(int)
+		maxConcurrent, _ := limit["max_concurrent_requests"].(int)
+
+		if seen[service] {
+			return nil, fmt.Errorf("request_limit: service %q is limited more than once", service)
+		}
+		seen[service] = true
+
+		if requestsPerSecond < 0 || burst < 0 || maxConcurrent < 0 {
+			return nil, fmt.Errorf("request_limit: the limits for service %q must not be negative", service)
+		}
+		if burst == 0 {
+			burst = int(math.Ceil(requestsPerSecond))
+		}
+
+		limiter := baremetal.NewRequestLimiter(requestsPerSecond, burst, maxConcurrent)
+		opts = append(opts, baremetal.ServiceRequestLimiter(baremetal.Service(service), limiter))
+	}
+	return
+}
+
+func ProviderConfig(d *schema.ResourceData) (clients interface{}, err error) {
+	var profile *configFileProfile
+	if profileName, ok := d.Get("config_file_profile").(string); ok && profileName != "" {
+		configFilePath, _ := d.Get("config_file_path").(string)
+		if configFilePath == "" {
+			configFilePath = defaultConfigFilePath
+		}
+		if profile, err = loadConfigFileProfile(configFilePath, profileName); err != nil {
+			return
+		}
+	}
+
+	authMode, _ := d.Get("auth").(string)
+	region := getProviderSetting(d, profile, "region")
 	disableAutoRetries, hasDisableRetries := d.Get("disable_auto_retries").(bool)
+	workRequestCreateRetries, _ := d.Get("work_request_create_retries").(int)
+	caBundlePath, _ := d.Get("ca_bundle_path").(string)
+	proxyURL, _ := d.Get("proxy_url").(string)
+	instanceMetadataURL, _ := d.Get("instance_metadata_url").(string)
+	federationURL, _ := d.Get("federation_url").(string)
 
 	// for internal use
 	urlTemplate := getEnvSetting("url_template", "")
@@ -247,58 +531,111 @@ func ProviderConfig(d *schema.ResourceData) (clients interface{}, err error) {
 		},
 	}
 
-	if allowInsecureTls == "true" {
-		log.Println("[WARN] USING INSECURE TLS")
-		clientOpts = append(clientOpts, baremetal.CustomTransport(
-			&http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
-		))
-	} else {
-		clientOpts = append(clientOpts, baremetal.CustomTransport(
-			&http.Transport{Proxy: http.ProxyFromEnvironment}),
-		)
+	transport, err := newTransport(transportConfig{
+		caBundlePath:     caBundlePath,
+		proxyURL:         proxyURL,
+		allowInsecureTLS: allowInsecureTls == "true",
+	})
+	if err != nil {
+		return
 	}
+	clientOpts = append(clientOpts, baremetal.CustomTransport(transport))
 
-	clientOpts = append(clientOpts, baremetal.CustomTransport(
-		&http.Transport{TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12}},
-	))
-
-	if hasKey && privateKeyBuffer != "" {
-		clientOpts = append(clientOpts, baremetal.PrivateKeyBytes([]byte(privateKeyBuffer)))
-	} else if hasKeyPath && privateKeyPath != "" {
-		clientOpts = append(clientOpts, baremetal.PrivateKeyFilePath(privateKeyPath))
-	} else {
-		err = errors.New("One of private_key or private_key_path is required")
-		return
+	if urlTemplate != "" {
+		clientOpts = append(clientOpts, baremetal.UrlTemplate(urlTemplate))
 	}
 
-	if hasKeyPass && privateKeyPassword != "" {
-		clientOpts = append(clientOpts, baremetal.PrivateKeyPassword(privateKeyPassword))
+	if region == "" {
+		region = defaultRegion
 	}
 
-	if hasRegion && region != "" {
-		clientOpts = append(clientOpts, baremetal.Region(region))
+	var tenancyOCID, userOCID, fingerprint string
+	if strings.EqualFold(authMode, authInstancePrincipal) {
+		// One federation serves every client of the provider, in every
+		// region, so that there is one session key and token to refresh.
+		federationOpts := append([]baremetal.NewClientOptionsFunc{}, clientOpts...)
+		federationOpts = append(federationOpts,
+			baremetal.Region(region),
+			baremetal.InstancePrincipalAuth(instanceMetadataURL, federationURL))
+		federation := baremetal.NewInstancePrincipalFederation(federationOpts...)
+		clientOpts = append(clientOpts, baremetal.SharedInstancePrincipalFederation(federation))
+	} else {
+		var keyOpts []baremetal.NewClientOptionsFunc
+		if tenancyOCID, userOCID, fingerprint, keyOpts, err = apiKeyCredentials(d, profile); err != nil {
+			return
+		}
+		clientOpts = append(clientOpts, keyOpts...)
 	}
 
 	if hasDisableRetries {
 		clientOpts = append(clientOpts, baremetal.DisableAutoRetries(disableAutoRetries))
 	}
 
-	if urlTemplate != "" {
-		clientOpts = append(clientOpts, baremetal.UrlTemplate(urlTemplate))
+	var retryOpts []baremetal.NewClientOptionsFunc
+	if retryOpts, err = retryPolicyOptions(d); err != nil {
+		return
 	}
+	clientOpts = append(clientOpts, retryOpts...)
+
+	workRequestRetryPolicy := &crud.WorkRequestRetryPolicy{
+		MaxRetries: workRequestCreateRetries,
+		Backoff:    workRequestRetryBackoff,
+		MaxBackoff: workRequestRetryMaxBackoff,
+	}
+
+	// newClients builds the clients for one region. Request limits apply per
+	// region, so each region gets its own limiters, which both of its
+	// clients share.
+	newClients := func(region string) (*OracleClients, error) {
+		limiterOpts, err := requestLimiterOptions(d)
+		if err != nil {
+			return nil, err
+		}
+		regionOpts := append([]baremetal.NewClientOptionsFunc{}, clientOpts...)
+		regionOpts = append(regionOpts, baremetal.Region(region))
+		regionOpts = append(regionOpts, limiterOpts...)
 
-	client, err := baremetal.NewClient(userOCID, tenancyOCID, fingerprint, clientOpts...)
+		client, err := baremetal.NewClient(userOCID, tenancyOCID, fingerprint, regionOpts...)
+		if err != nil {
+			return nil, err
+		}
 
-	clientOpts = append(clientOpts, baremetal.DisableNotFoundRetries(true))
-	clientWithoutNotFoundRetries, err := baremetal.NewClient(userOCID, tenancyOCID, fingerprint, clientOpts...)
-	clients = &OracleClients{
-		client: client,
-		clientWithoutNotFoundRetries: clientWithoutNotFoundRetries,
+		regionOpts = append(regionOpts, baremetal.DisableNotFoundRetries(true))
+		clientWithoutNotFoundRetries, err := baremetal.NewClient(userOCID, tenancyOCID, fingerprint, regionOpts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &OracleClients{
+			client:                       client,
+			clientWithoutNotFoundRetries: clientWithoutNotFoundRetries,
+			workRequestRetryPolicy:       workRequestRetryPolicy,
+			region:                       region,
+		}, nil
+	}
+
+	providerClients, err := newClients(region)
+	if err != nil {
+		return
 	}
+	providerClients.newRegionalClients = newClients
+	clients = providerClients
 	return
 }
 
 type OracleClients struct {
 	client                       *baremetal.Client
 	clientWithoutNotFoundRetries *baremetal.Client
+	workRequestRetryPolicy       *crud.WorkRequestRetryPolicy
+
+	// stopContext is done once Terraform asks the provider to stop, e.g. on
+	// an interrupt. See withContext.
+	stopContext context.Context
+
+	// region is the region the clients above target. Clients for other
+	// regions are built by newRegionalClients on first use; see ForRegion.
+	region             string
+	newRegionalClients func(region string) (*OracleClients, error)
+	mutex              sync.Mutex
+	regionalClients    map[string]*OracleClients
 }
@@ -3,7 +3,13 @@
 package provider
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
@@ -18,12 +24,13 @@ var testAccProviders map[string]terraform.ResourceProvider
 func init() {
 	testAccClient = GetTestProvider().client
 
-	testAccProvider = Provider(func(d *schema.ResourceData) (interface{}, error) {
+	provider := Provider(func(d *schema.ResourceData) (interface{}, error) {
 		return GetTestProvider(), nil
-	}).(*schema.Provider)
+	})
+	testAccProvider = provider.(*diffCustomizingProvider).Provider
 
 	testAccProviders = map[string]terraform.ResourceProvider{
-		"oci": testAccProvider,
+		"oci": provider,
 	}
 }
 
@@ -216,7 +223,7 @@ func TestProvider(t *testing.T) {
 	client := &OracleClients{}
 	if err := Provider(func(d *schema.ResourceData) (interface{}, error) {
 		return client, nil
-	}).(*schema.Provider).InternalValidate(); err != nil {
+	}).(*diffCustomizingProvider).InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
 	}
 }
@@ -277,3 +284,237 @@ func TestProviderConfig(t *testing.T) {
 	_, ok := client.(*OracleClients)
 	assert.True(t, ok)
 }
+
+// newStandInClient configures a provider against a stand-in API served by
+// handler.
+func newStandInClient(handler http.HandlerFunc, configure func(d *schema.ResourceData)) (clients *OracleClients, closeServer func(), err error) {
+	server := httptest.NewServer(handler)
+
+	os.Setenv("OCI_url_template", server.URL+"/%s/%s")
+	defer os.Unsetenv("OCI_url_template")
+
+	d := newProviderResourceData()
+	d.Set("tenancy_ocid", testTenancyOCID)
+	d.Set("user_ocid", testUserOCID)
+	d.Set("fingerprint", testKeyFingerPrint)
+	d.Set("private_key", testPrivateKey)
+	d.Set("private_key_password", "password")
+	configure(d)
+
+	m, err := ProviderConfig(d)
+	if err != nil {
+		server.Close()
+		return nil, nil, err
+	}
+	return m.(*OracleClients), server.Close, nil
+}
+
+// standInProviders are providers for resource.UnitTest whose clients are
+// those of newStandInClient.
+func standInProviders(clients *OracleClients) map[string]terraform.ResourceProvider {
+	return map[string]terraform.ResourceProvider{
+		"oci": Provider(func(d *schema.ResourceData) (interface{}, error) {
+			return clients, nil
+		}),
+	}
+}
+
+func failingStandIn(requests *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"code":"InternalError","message":"stand-in failure"}`))
+	}
+}
+
+// newRetryTestClient configures a provider against a stand-in API that always
+// fails with a 500 and returns the number of requests it received.
+func newRetryTestClient(t *testing.T, configure func(d *schema.ResourceData)) (requests func(call func(*baremetal.Client)) int, err error) {
+	var count int32
+	clients, closeServer, err := newStandInClient(failingStandIn(&count), configure)
+	if err != nil {
+		return nil, err
+	}
+	return func(call func(*baremetal.Client)) int {
+		defer closeServer()
+		call(clients.client)
+		return int(atomic.LoadInt32(&count))
+	}, nil
+}
+
+func TestProviderConfig_retryPolicy(t *testing.T) {
+	// Retries don't sleep under TEST, so attempts are bounded only by the
+	// retry time that the backoff consumes.
+	os.Setenv("TEST", "true")
+	defer os.Unsetenv("TEST")
+
+	getInstance := func(client *baremetal.Client) {
+		_, err := client.GetInstance("ocid1.instance.oc1..standin")
+		assert.NotNil(t, err)
+	}
+
+	// Polynomial backoff waits 1s then 4s, which uses up a 5 second budget.
+	requests, err := newRetryTestClient(t, func(d *schema.ResourceData) {
+		d.Set("max_retry_duration_seconds", 5)
+	})
+	if assert.Nil(t, err) {
+		assert.Equal(t, 3, requests(getInstance))
+	}
+
+	// Overrides take precedence over max_retry_duration_seconds.
+	requests, err = newRetryTestClient(t, func(d *schema.ResourceData) {
+		d.Set("max_retry_duration_seconds", 5)
+		d.Set("retry_duration_overrides", map[string]interface{}{"500": 0})
+	})
+	if assert.Nil(t, err) {
+		assert.Equal(t, 1, requests(getInstance))
+	}
+
+	requests, err = newRetryTestClient(t, func(d *schema.ResourceData) {
+		d.Set("retry_duration_overrides", map[string]interface{}{"500:InternalError": 1})
+	})
+	if assert.Nil(t, err) {
+		assert.Equal(t, 2, requests(getInstance))
+	}
+
+	_, err = newRetryTestClient(t, func(d *schema.ResourceData) {
+		d.Set("retry_duration_overrides", map[string]interface{}{"throttled": 60})
+	})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `"throttled" must be an HTTP status`)
+	}
+}
+
+func TestProviderConfig_requestLimit(t *testing.T) {
+	var inFlight, maxInFlight int32
+	clients, closeServer, err := newStandInClient(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			observed := atomic.LoadInt32(&maxInFlight)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("content-type", "application/json")
+		w.Write([]byte(`{"id":"ocid1.instance.oc1..standin","lifecycleState":"RUNNING"}`))
+	}, func(d *schema.ResourceData) {
+		d.Set("request_limit", []interface{}{
+			map[string]interface{}{"service": "core", "max_concurrent_requests": 2},
+		})
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := clients.client.GetInstance("ocid1.instance.oc1..standin")
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxInFlight))
+}
+
+func TestProviderConfig_requestLimitCountsRetries(t *testing.T) {
+	os.Setenv("TEST", "true")
+	defer os.Unsetenv("TEST")
+
+	var requests int32
+	clients, closeServer, err := newStandInClient(failingStandIn(&requests), func(d *schema.ResourceData) {
+		// Three attempts: the first and two retries.
+		d.Set("max_retry_duration_seconds", 5)
+		d.Set("request_limit", []interface{}{
+			map[string]interface{}{"service": "core", "requests_per_second": 10.0, "burst": 1},
+		})
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	start := time.Now()
+	_, err = clients.client.GetInstance("ocid1.instance.oc1..standin")
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	// The retries each waited for a token.
+	assert.True(t, time.Since(start) >= 190*time.Millisecond)
+
+	_, _, err = newStandInClient(failingStandIn(&requests), func(d *schema.ResourceData) {
+		d.Set("request_limit", []interface{}{
+			map[string]interface{}{"service": "identity", "requests_per_second": 1.0},
+			map[string]interface{}{"service": "identity", "max_concurrent_requests": 1},
+		})
+	})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `service "identity" is limited more than once`)
+	}
+}
+
+func TestProviderConfig_requestLimitSignsAfterWaiting(t *testing.T) {
+	var mutex sync.Mutex
+	var skews []time.Duration
+	clients, closeServer, err := newStandInClient(func(w http.ResponseWriter, r *http.Request) {
+		if date, err := http.ParseTime(r.Header.Get("date")); err == nil {
+			mutex.Lock()
+			skews = append(skews, time.Since(date))
+			mutex.Unlock()
+		}
+		w.Header().Set("content-type", "application/json")
+		w.Write([]byte(`{"id":"ocid1.instance.oc1..standin","lifecycleState":"RUNNING"}`))
+	}, func(d *schema.ResourceData) {
+		d.Set("request_limit", []interface{}{
+			map[string]interface{}{"service": "core", "requests_per_second": 0.5, "burst": 1},
+		})
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	// The second request waits two seconds for a token, and is only signed
+	// once it has one.
+	for i := 0; i < 2; i++ {
+		_, err := clients.client.GetInstance("ocid1.instance.oc1..standin")
+		assert.Nil(t, err)
+	}
+	if assert.Len(t, skews, 2) {
+		// The date header has a resolution of one second.
+		assert.True(t, skews[1] < 1500*time.Millisecond, "request was signed %s before it was sent", skews[1])
+	}
+}
+
+func TestProvider_stopInterruptsRetries(t *testing.T) {
+	var requests int32
+	clients, closeServer, err := newStandInClient(failingStandIn(&requests), func(d *schema.ResourceData) {})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	p := Provider(func(d *schema.ResourceData) (interface{}, error) {
+		return clients, nil
+	}).(*diffCustomizingProvider)
+	if !assert.Nil(t, p.Configure(terraform.NewResourceConfig(nil))) {
+		return
+	}
+
+	regional, err := clientsForRegion(resourcesMap()["oci_core_drg"].Data(nil), p.Meta())
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// Without the interrupt, the 500s would be retried for minutes.
+	time.AfterFunc(100*time.Millisecond, func() { p.Stop() })
+	start := time.Now()
+	_, err = regional.client.GetInstance("ocid1.instance.oc1..standin")
+	assert.NotNil(t, err)
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// defaultRegion is the region the SDK targets when none is configured.
+const defaultRegion = "us-phoenix-1"
+
+var regionRegex = regexp.MustCompile(`^[a-z]+-[a-z]+-[0-9]+$`)
+
+// regionShortCodes maps the short region codes that some API responses
+// carry, e.g. Instance.Region, to region names.
+var regionShortCodes = map[string]string{
+	"phx": "us-phoenix-1",
+	"iad": "us-ashburn-1",
+	"fra": "eu-frankfurt-1",
+	"lhr": "uk-london-1",
+}
+
+// validateRegion checks that region is a region name rather than, e.g., a
+// short region code, which would otherwise build clients for endpoints that
+// don't exist.
+func validateRegion(region string) error {
+	if regionRegex.MatchString(region) {
+		return nil
+	}
+	if name, ok := regionShortCodes[region]; ok {
+		return fmt.Errorf("%q is a region code, use the region name %q instead", region, name)
+	}
+	return fmt.Errorf("%q is not a region name, e.g. us-phoenix-1", region)
+}
+
+func validateRegionArgument(v interface{}, k string) (ws []string, es []error) {
+	if region, _ := v.(string); region != "" {
+		if err := validateRegion(region); err != nil {
+			es = append(es, fmt.Errorf("%s: %s", k, err))
+		}
+	}
+	return
+}
+
+// ForRegion returns the clients for region, building them on first use.
+// An empty region selects the provider's region.
+func (c *OracleClients) ForRegion(region string) (*OracleClients, error) {
+	if region == "" || region == c.region {
+		return c, nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if clients, ok := c.regionalClients[region]; ok {
+		return clients, nil
+	}
+	if err := validateRegion(region); err != nil {
+		return nil, err
+	}
+	if c.newRegionalClients == nil {
+		return nil, fmt.Errorf("Clients for region %s are not available", region)
+	}
+
+	clients, err := c.newRegionalClients(region)
+	if err != nil {
+		return nil, fmt.Errorf("Could not create clients for region %s: %s", region, err)
+	}
+	if c.regionalClients == nil {
+		c.regionalClients = map[string]*OracleClients{}
+	}
+	c.regionalClients[region] = clients
+	return clients, nil
+}
+
+// clientsForRegion returns the clients for the region a resource or data
+// source is in: its region argument, or else the provider's region.
+//
+// The clients returned stop their API calls when Terraform is interrupted.
+func clientsForRegion(d *schema.ResourceData, m interface{}) (*OracleClients, error) {
+	providerClients := m.(*OracleClients)
+	region, _ := d.Get("region").(string)
+	clients, err := providerClients.ForRegion(region)
+	if err != nil || providerClients.stopContext == nil {
+		return clients, err
+	}
+	return clients.withContext(providerClients.stopContext), nil
+}
+
+// withContext returns a copy of c whose clients make their API calls under
+// ctx.
+func (c *OracleClients) withContext(ctx context.Context) *OracleClients {
+	return &OracleClients{
+		client:                       c.client.WithContext(ctx),
+		clientWithoutNotFoundRetries: c.clientWithoutNotFoundRetries.WithContext(ctx),
+		workRequestRetryPolicy:       c.workRequestRetryPolicy,
+		stopContext:                  ctx,
+		region:                       c.region,
+	}
+}
+
+// regionalResources adds a region argument to every resource, and records the
+// region each resource is in so that refresh, update and delete target it.
+func regionalResources(resources map[string]*schema.Resource) map[string]*schema.Resource {
+	for _, r := range resources {
+		r.Schema["region"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ForceNew:     true,
+			ValidateFunc: validateRegionArgument,
+			Description:  "The region to create the resource in. Defaults to the provider's region.",
+		}
+		r.Create = schema.CreateFunc(recordRegion(r.Create))
+		r.Read = schema.ReadFunc(recordRegion(r.Read))
+		if r.Importer != nil {
+			r.Importer.State = importWithRegion(r.Importer.State)
+		}
+	}
+	return resources
+}
+
+// regionalDataSources adds a region argument to every data source.
+func regionalDataSources(dataSources map[string]*schema.Resource) map[string]*schema.Resource {
+	for _, r := range dataSources {
+		r.Schema["region"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validateRegionArgument,
+			Description:  "The region to read from. Defaults to the provider's region.",
+		}
+		r.Read = schema.ReadFunc(recordRegion(r.Read))
+	}
+	return dataSources
+}
+
+func recordRegion(f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	if f == nil {
+		return nil
+	}
+	return func(d *schema.ResourceData, m interface{}) error {
+		err := f(d, m)
+		// The region is recorded even when f fails, as long as something
+		// was created, so that the partial resource can be found again.
+		if d.Id() != "" {
+			if region, _ := d.Get("region").(string); region == "" {
+				d.Set("region", m.(*OracleClients).region)
+			}
+		}
+		return err
+	}
+}
+
+// importWithRegion lets resources in other regions be imported with an ID
+// of the form <region>/<id>.
+func importWithRegion(f schema.StateFunc) schema.StateFunc {
+	return func(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		if parts := strings.SplitN(d.Id(), "/", 2); len(parts) == 2 && regionRegex.MatchString(parts[0]) {
+			d.SetId(parts[1])
+			d.Set("region", parts[0])
+		}
+		return f(d, m)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProviderConfig_regions(t *testing.T) {
+	var mutex sync.Mutex
+	var requestedRegions []string
+	clients, closeServer, err := newStandInClient(func(w http.ResponseWriter, r *http.Request) {
+		// Requests go to /<service>/<region>/<version>/...
+		mutex.Lock()
+		requestedRegions = append(requestedRegions, strings.Split(r.URL.Path, "/")[2])
+		mutex.Unlock()
+		w.Header().Set("content-type", "application/json")
+		w.Write([]byte(`{"id":"ocid1.drg.oc1..standin","compartmentId":"ocid1.compartment.oc1..standin","lifecycleState":"AVAILABLE"}`))
+	}, func(d *schema.ResourceData) {
+		d.Set("region", "us-phoenix-1")
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer closeServer()
+
+	// Clients for other regions are built once, on first use.
+	ashburn, err := clients.ForRegion("us-ashburn-1")
+	assert.Nil(t, err)
+	again, _ := clients.ForRegion("us-ashburn-1")
+	assert.True(t, ashburn == again)
+	phoenix, _ := clients.ForRegion("")
+	assert.True(t, clients == phoenix)
+
+	// Region codes and other non-region names are rejected rather than
+	// turned into endpoints that don't exist.
+	_, err = clients.ForRegion("phx")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `use the region name "us-phoenix-1"`)
+	}
+	_, err = clients.ForRegion("Phoenix")
+	assert.NotNil(t, err)
+	_, es := validateRegionArgument("iad", "region")
+	assert.Len(t, es, 1)
+
+	drg := resourcesMap()["oci_core_drg"]
+
+	// A resource without a region is read from the provider's region, which
+	// is then recorded.
+	state := drg.Data(&terraform.InstanceState{ID: "ocid1.drg.oc1..standin"})
+	assert.Nil(t, drg.Read(state, clients))
+	assert.Equal(t, "us-phoenix-1", state.Get("region"))
+
+	// Importing <region>/<id> reads the resource from that region.
+	state = drg.Data(&terraform.InstanceState{ID: "us-ashburn-1/ocid1.drg.oc1..standin"})
+	imported, err := drg.Importer.State(state, clients)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "ocid1.drg.oc1..standin", imported[0].Id())
+		assert.Nil(t, drg.Read(imported[0], clients))
+		assert.Equal(t, "us-ashburn-1", imported[0].Get("region"))
+	}
+
+	assert.Equal(t, []string{"us-phoenix-1", "us-ashburn-1"}, requestedRegions)
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// transportConfig holds the settings that shape the provider's HTTP
+// transport.
+type transportConfig struct {
+	// caBundlePath is a PEM file of CA certificates to trust in addition to
+	// the system roots, e.g. that of a TLS-intercepting proxy.
+	caBundlePath string
+	// proxyURL is the proxy to send requests through. When empty, the
+	// HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables apply.
+	proxyURL string
+	// allowInsecureTLS disables certificate verification. For testing only.
+	allowInsecureTLS bool
+}
+
+// newTransport builds the single transport that all clients share, so that
+// the proxy, the minimum TLS version and the trusted roots all apply.
+func newTransport(config transportConfig) (*http.Transport, error) {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+
+	if config.proxyURL != "" {
+		proxy, err := url.Parse(config.proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("proxy_url %q is not a valid URL: %s", config.proxyURL, err)
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("proxy_url %q must be an http, https or socks5 URL", config.proxyURL)
+		}
+		if proxy.Host == "" {
+			return nil, fmt.Errorf("proxy_url %q has no host", config.proxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if config.caBundlePath != "" {
+		rootCAs, err := loadCABundle(config.caBundlePath)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig.RootCAs = rootCAs
+	}
+
+	if config.allowInsecureTLS {
+		log.Println("[WARN] USING INSECURE TLS")
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+
+	return transport, nil
+}
+
+// loadCABundle returns the system roots plus the certificates in path.
+func loadCABundle(path string) (*x509.CertPool, error) {
+	expandedPath, err := homedir.Expand(path)
+	if err != nil {
+		return nil, err
+	}
+	pemCerts, err := ioutil.ReadFile(expandedPath)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read ca_bundle_path %s: %s", path, err)
+	}
+
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		log.Printf("[WARN] Could not load the system root CAs, only trusting %s: %s", path, err)
+		rootCAs = x509.NewCertPool()
+	}
+	if !rootCAs.AppendCertsFromPEM(pemCerts) {
+		return nil, fmt.Errorf("ca_bundle_path %s does not contain any PEM encoded certificates", path)
+	}
+	return rootCAs, nil
+}
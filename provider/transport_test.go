@@ -0,0 +1,96 @@
+// Copyright (c) 2017, Oracle and/or its affiliates. All rights reserved.
+
+package provider
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTransport_defaults(t *testing.T) {
+	transport, err := newTransport(transportConfig{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+	assert.False(t, transport.TLSClientConfig.InsecureSkipVerify)
+	assert.Nil(t, transport.TLSClientConfig.RootCAs)
+
+	// Without proxy_url, the proxy environment variables apply.
+	assert.NotNil(t, transport.Proxy)
+}
+
+func TestNewTransport_proxyURL(t *testing.T) {
+	transport, err := newTransport(transportConfig{proxyURL: "http://proxy.example.com:8080"})
+	if !assert.Nil(t, err) {
+		return
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://iaas.us-phoenix-1.oraclecloud.com/20160918/instances", nil)
+	proxy, err := transport.Proxy(req)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "http://proxy.example.com:8080", proxy.String())
+	}
+	assert.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+
+	for _, invalid := range []string{"ftp://proxy.example.com", "http://", "://proxy"} {
+		_, err = newTransport(transportConfig{proxyURL: invalid})
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestNewTransport_caBundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "oci-ca-bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	bundlePath := filepath.Join(dir, "ca.pem")
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err = ioutil.WriteFile(bundlePath, bundle, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The system roots don't trust the test server.
+	transport, _ := newTransport(transportConfig{})
+	_, err = (&http.Client{Transport: transport}).Get(server.URL)
+	assert.NotNil(t, err)
+
+	// The bundle is trusted, and the proxy and minimum TLS version still
+	// apply alongside it.
+	transport, err = newTransport(transportConfig{caBundlePath: bundlePath, proxyURL: "http://proxy.example.com:8080"})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	proxy, _ := transport.Proxy(req)
+	assert.Equal(t, "proxy.example.com:8080", proxy.Host)
+
+	transport.Proxy = nil
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if assert.Nil(t, err) {
+		resp.Body.Close()
+	}
+
+	emptyPath := filepath.Join(dir, "empty.pem")
+	ioutil.WriteFile(emptyPath, []byte("not a certificate"), 0600)
+	_, err = newTransport(transportConfig{caBundlePath: emptyPath})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "does not contain any PEM encoded certificates")
+	}
+
+	_, err = newTransport(transportConfig{caBundlePath: filepath.Join(dir, "missing.pem")})
+	assert.NotNil(t, err)
+}